@@ -4,7 +4,6 @@ go 1.24.0
 
 require (
 	github.com/dustin/go-humanize v1.0.1
-	github.com/georgysavva/scany/v2 v2.1.3
 	github.com/go-sql-driver/mysql v1.9.0
 	github.com/jackc/pgx/v5 v5.7.2
 	github.com/mekramy/goconsole v0.0.2
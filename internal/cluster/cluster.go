@@ -0,0 +1,122 @@
+// Package cluster selects healthy replica connections for the postgres and mysql clusters.
+package cluster
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Strategy specifies how a replica is selected for a read.
+type Strategy int
+
+const (
+	RoundRobin       Strategy = iota // rotates over the healthy replicas
+	LeastConnections                 // picks the healthy replica with the fewest connections in use
+)
+
+// New creates a new replica set. All replicas are healthy until a health check fails.
+// `load` returns the number of connections in use, and `ping` checks the replica health.
+func New[C any](nodes []C, strategy Strategy, load func(C) int, ping func(context.Context, C) error) *Replicas[C] {
+	return &Replicas[C]{
+		nodes:    nodes,
+		ejected:  make([]atomic.Bool, len(nodes)),
+		strategy: strategy,
+		load:     load,
+		ping:     ping,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Replicas holds the replica connections and their health.
+type Replicas[C any] struct {
+	nodes    []C
+	ejected  []atomic.Bool
+	next     atomic.Uint64
+	strategy Strategy
+	load     func(C) int
+	ping     func(context.Context, C) error
+	stop     chan struct{}
+	once     sync.Once
+	wg       sync.WaitGroup
+}
+
+// Pick selects a healthy replica, or reports false if none is available.
+func (r *Replicas[C]) Pick() (C, bool) {
+	var picked C
+	found := false
+	switch r.strategy {
+	case LeastConnections:
+		least := 0
+		for i, node := range r.nodes {
+			if r.ejected[i].Load() {
+				continue
+			}
+
+			if load := r.load(node); !found || load < least {
+				picked, least, found = node, load, true
+			}
+		}
+	default:
+		start := r.next.Add(1)
+		for i := range r.nodes {
+			idx := int((start + uint64(i)) % uint64(len(r.nodes)))
+			if !r.ejected[idx].Load() {
+				return r.nodes[idx], true
+			}
+		}
+	}
+	return picked, found
+}
+
+// Check pings all replicas and ejects the failing ones until a later check succeeds.
+func (r *Replicas[C]) Check(ctx context.Context) {
+	for i, node := range r.nodes {
+		r.ejected[i].Store(r.ping(ctx, node) != nil)
+	}
+}
+
+// Healthy returns the number of healthy replicas.
+func (r *Replicas[C]) Healthy() int {
+	count := 0
+	for i := range r.ejected {
+		if !r.ejected[i].Load() {
+			count++
+		}
+	}
+	return count
+}
+
+// Watch checks the replicas health every interval in the background until Close is called.
+// Each check times out after the interval.
+func (r *Replicas[C]) Watch(interval time.Duration) {
+	if interval <= 0 || len(r.nodes) == 0 {
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				r.Check(ctx)
+				cancel()
+			}
+		}
+	}()
+}
+
+// Close stops the background health checks.
+func (r *Replicas[C]) Close() {
+	r.once.Do(func() { close(r.stop) })
+	r.wg.Wait()
+}
@@ -0,0 +1,89 @@
+package cluster_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mekramy/gosql/internal/cluster"
+)
+
+type node struct {
+	name string
+	load int
+	down bool
+}
+
+func load(n *node) int { return n.load }
+
+func ping(_ context.Context, n *node) error {
+	if n.down {
+		return errors.New("down")
+	}
+	return nil
+}
+
+func TestReplicas_RoundRobin(t *testing.T) {
+	a, b, c := &node{name: "a"}, &node{name: "b"}, &node{name: "c"}
+	replicas := cluster.New([]*node{a, b, c}, cluster.RoundRobin, load, ping)
+
+	seen := make(map[string]int)
+	for range 6 {
+		n, ok := replicas.Pick()
+		if !ok {
+			t.Fatalf("expected a replica")
+		}
+		seen[n.name]++
+	}
+	if seen["a"] != 2 || seen["b"] != 2 || seen["c"] != 2 {
+		t.Fatalf("expected even distribution, got %v", seen)
+	}
+
+	b.down = true
+	replicas.Check(context.Background())
+	if healthy := replicas.Healthy(); healthy != 2 {
+		t.Fatalf("expected 2 healthy replicas, got %d", healthy)
+	}
+	for range 6 {
+		if n, _ := replicas.Pick(); n == b {
+			t.Fatalf("expected ejected replica to be skipped")
+		}
+	}
+
+	b.down = false
+	replicas.Check(context.Background())
+	if healthy := replicas.Healthy(); healthy != 3 {
+		t.Fatalf("expected recovered replica, got %d healthy", healthy)
+	}
+}
+
+func TestReplicas_LeastConnections(t *testing.T) {
+	a, b := &node{name: "a", load: 3}, &node{name: "b", load: 1}
+	replicas := cluster.New([]*node{a, b}, cluster.LeastConnections, load, ping)
+
+	if n, _ := replicas.Pick(); n != b {
+		t.Fatalf("expected least loaded replica b, got %s", n.name)
+	}
+
+	b.down = true
+	replicas.Check(context.Background())
+	if n, _ := replicas.Pick(); n != a {
+		t.Fatalf("expected healthy replica a, got %s", n.name)
+	}
+
+	a.down = true
+	replicas.Check(context.Background())
+	if _, ok := replicas.Pick(); ok {
+		t.Fatalf("expected no replica when all are ejected")
+	}
+}
+
+func TestReplicas_Empty(t *testing.T) {
+	replicas := cluster.New(nil, cluster.RoundRobin, load, ping)
+	replicas.Watch(0)
+	defer replicas.Close()
+
+	if _, ok := replicas.Pick(); ok {
+		t.Fatalf("expected no replica")
+	}
+}
@@ -0,0 +1,87 @@
+// Package hook runs the query hooks of the postgres and mysql executors.
+package hook
+
+import (
+	"context"
+	"time"
+)
+
+// Operation is the kind of database call observed by a hook.
+type Operation string
+
+const (
+	OpExec     Operation = "exec"      // command without result rows
+	OpQuery    Operation = "query"     // query returning rows
+	OpQueryRow Operation = "query_row" // query returning a single row
+	OpCopy     Operation = "copy"      // bulk copy
+)
+
+// Event describes an observed database call.
+type Event struct {
+	Operation Operation
+	SQL       string
+	Args      []any
+	Duration  time.Duration // set before After is called
+	Rows      int64         // rows affected, copied or read, -1 if unknown, set before After is called
+	Err       error         // set before After is called
+}
+
+// Hook observes database calls.
+type Hook interface {
+	// Before is called before the database call.
+	// The returned context is passed to the database call and After, e.g. to carry a tracing span.
+	Before(ctx context.Context, event *Event) context.Context
+
+	// After is called once the database call completes, or once the result rows are consumed.
+	After(ctx context.Context, event *Event)
+}
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying the hooks in addition to the hooks already carried.
+func WithContext(ctx context.Context, hooks ...Hook) context.Context {
+	return context.WithValue(ctx, contextKey{}, append(FromContext(ctx), hooks...))
+}
+
+// FromContext returns the hooks carried by ctx.
+func FromContext(ctx context.Context) []Hook {
+	hooks, _ := ctx.Value(contextKey{}).([]Hook)
+	return hooks[:len(hooks):len(hooks)]
+}
+
+// Finish completes an observed database call with the rows count and error.
+type Finish func(rows int64, err error)
+
+// Start runs the Before callbacks of the hooks followed by the hooks carried by ctx.
+// It returns the context of the database call and the function running the After callbacks, which runs at most once.
+// The returned context no longer carries the hooks, so nested executors don't run them twice.
+func Start(ctx context.Context, hooks []Hook, op Operation, sql string, args []any) (context.Context, Finish) {
+	if carried := FromContext(ctx); len(carried) > 0 {
+		hooks = append(hooks[:len(hooks):len(hooks)], carried...)
+		ctx = context.WithValue(ctx, contextKey{}, []Hook(nil))
+	}
+	if len(hooks) == 0 {
+		return ctx, func(int64, error) {}
+	}
+
+	event := &Event{Operation: op, SQL: sql, Args: args, Rows: -1}
+	for _, h := range hooks {
+		ctx = h.Before(ctx, event)
+	}
+
+	start := time.Now()
+	finished := false
+	return ctx, func(rows int64, err error) {
+		if finished {
+			return
+		}
+		finished = true
+
+		event.Duration = time.Since(start)
+		event.Rows = rows
+		event.Err = err
+		for _, h := range hooks {
+			h.After(ctx, event)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package hook_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mekramy/gosql/internal/hook"
+)
+
+type recorder struct {
+	name   string
+	calls  *[]string
+	events []hook.Event
+}
+
+func (r *recorder) Before(ctx context.Context, event *hook.Event) context.Context {
+	*r.calls = append(*r.calls, "before "+r.name)
+	return ctx
+}
+
+func (r *recorder) After(ctx context.Context, event *hook.Event) {
+	*r.calls = append(*r.calls, "after "+r.name)
+	r.events = append(r.events, *event)
+}
+
+func TestStart(t *testing.T) {
+	var calls []string
+	conn := &recorder{name: "conn", calls: &calls}
+	call := &recorder{name: "call", calls: &calls}
+
+	ctx := hook.WithContext(context.Background(), call)
+	ctx, finish := hook.Start(ctx, []hook.Hook{conn}, hook.OpExec, "DELETE FROM users", []any{1})
+	if ctx == nil {
+		t.Fatalf("expected context")
+	}
+
+	failure := errors.New("failure")
+	finish(3, failure)
+	finish(4, nil) // ignored
+
+	expected := "before conn,before call,after conn,after call"
+	if got := strings.Join(calls, ","); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+
+	if len(conn.events) != 1 {
+		t.Fatalf("expected a single event, got %d", len(conn.events))
+	}
+
+	event := conn.events[0]
+	if event.Operation != hook.OpExec || event.SQL != "DELETE FROM users" || event.Rows != 3 || !errors.Is(event.Err, failure) {
+		t.Fatalf("unexpected event %+v", event)
+	}
+}
+
+func TestStart_Nested(t *testing.T) {
+	var calls []string
+	call := &recorder{name: "call", calls: &calls}
+
+	ctx := hook.WithContext(context.Background(), call)
+	outer, finishOuter := hook.Start(ctx, nil, hook.OpExec, "DELETE FROM users", nil)
+	if len(hook.FromContext(outer)) != 0 {
+		t.Fatalf("expected the carried hooks to be consumed")
+	}
+
+	_, finishInner := hook.Start(outer, nil, hook.OpExec, "DELETE FROM users", nil)
+	finishInner(1, nil)
+	finishOuter(1, nil)
+
+	expected := "before call,after call"
+	if got := strings.Join(calls, ","); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestStart_NoHooks(t *testing.T) {
+	ctx := context.Background()
+	got, finish := hook.Start(ctx, nil, hook.OpQuery, "SELECT 1", nil)
+	if got != ctx {
+		t.Fatalf("expected the same context")
+	}
+	finish(0, nil)
+}
+
+func TestSlowQueryLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	slow := hook.SlowQueryLogger(logger, 10*time.Millisecond)
+
+	fast := &hook.Event{Operation: hook.OpQuery, SQL: "SELECT 1", Duration: time.Millisecond}
+	slow.After(context.Background(), fast)
+	if buf.Len() != 0 {
+		t.Fatalf("expected fast query to not be logged, got %s", buf.String())
+	}
+
+	event := &hook.Event{
+		Operation: hook.OpQuery,
+		SQL:       "SELECT * FROM users WHERE email = $1",
+		Args:      []any{"secret@example.com"},
+		Duration:  20 * time.Millisecond,
+	}
+	slow.After(context.Background(), event)
+
+	log := buf.String()
+	if !strings.Contains(log, "slow query") || !strings.Contains(log, "args=1") {
+		t.Fatalf("expected slow query log, got %s", log)
+	}
+	if strings.Contains(log, "secret@example.com") {
+		t.Fatalf("expected arguments to be redacted, got %s", log)
+	}
+}
@@ -0,0 +1,43 @@
+package hook
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SlowQueryLogger returns a hook logging the calls that take at least threshold as warnings.
+// Arguments are never logged, only their count, since they may contain personal data or secrets.
+func SlowQueryLogger(logger *slog.Logger, threshold time.Duration) Hook {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slowQueryLogger{logger: logger, threshold: threshold}
+}
+
+type slowQueryLogger struct {
+	logger    *slog.Logger
+	threshold time.Duration
+}
+
+func (l *slowQueryLogger) Before(ctx context.Context, _ *Event) context.Context {
+	return ctx
+}
+
+func (l *slowQueryLogger) After(ctx context.Context, event *Event) {
+	if event.Duration < l.threshold {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", string(event.Operation)),
+		slog.String("sql", event.SQL),
+		slog.Int("args", len(event.Args)),
+		slog.Duration("duration", event.Duration),
+		slog.Int64("rows", event.Rows),
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	}
+	l.logger.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs...)
+}
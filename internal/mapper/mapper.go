@@ -0,0 +1,304 @@
+// Package mapper resolves and caches the column mapping of struct types
+// shared by the postgres and mysql repositories.
+package mapper
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	scannerType = reflect.TypeFor[sql.Scanner]()
+	valuerType  = reflect.TypeFor[driver.Valuer]()
+	timeType    = reflect.TypeFor[time.Time]()
+)
+
+// ErrUnknownColumn is returned when a column does not match any struct field.
+var ErrUnknownColumn = errors.New("column does not match any struct field")
+
+// Mode specifies the operation struct fields are resolved for.
+type Mode int
+
+const (
+	Select Mode = iota // includes all tagged fields
+	Insert             // skips readonly fields, and zero omitempty or default fields
+	Update             // skips readonly and pk fields, and zero omitempty fields
+	Bulk               // skips readonly fields, zero omitempty or default fields resolve to Default
+)
+
+// Default is a placeholder value for fields that should be written as DEFAULT in bulk mode.
+type Default struct{}
+
+// New creates a new Mapper that quotes column names using the given function.
+// If names is not nil, untagged fields are mapped to the column returned by names
+// and handled like tagged fields, otherwise they are only matched loosely on reads.
+func New(quote func(name string) string, names func(field string) string) *Mapper {
+	return &Mapper{quote: quote, names: names}
+}
+
+// Mapper resolves the column mapping of struct types and caches it per type.
+type Mapper struct {
+	quote func(string) string
+	names func(string) string
+	cache sync.Map
+}
+
+// Of returns the cached metadata of a struct type, or nil if typ is not a struct.
+func (m *Mapper) Of(typ reflect.Type) *Metadata {
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if meta, ok := m.cache.Load(typ); ok {
+		return meta.(*Metadata)
+	}
+
+	meta, _ := m.cache.LoadOrStore(typ, m.build(typ))
+	return meta.(*Metadata)
+}
+
+func (m *Mapper) build(typ reflect.Type) *Metadata {
+	meta := &Metadata{
+		Fields: appendFields(make([]Field, 0, typ.NumField()), typ, "", nil, m.names),
+		Keys:   make([]string, 0),
+		tagged: make(map[string]int),
+		loose:  make(map[string]int),
+	}
+
+	for i := range meta.Fields {
+		field := &meta.Fields[i]
+		if !field.Tagged {
+			meta.loose[normalize(field.Name)] = i
+			continue
+		}
+
+		field.Quoted = m.quote(field.Name)
+		meta.tagged[field.Name] = i
+		meta.Columns = append(meta.Columns, field.Quoted)
+
+		if field.PK {
+			meta.Keys = append(meta.Keys, field.Name)
+		}
+
+		if field.SoftDelete && meta.SoftDelete == "" {
+			meta.SoftDelete = field.Name
+		}
+
+		if field.AutoIncrement && meta.AutoIncrement == nil {
+			meta.AutoIncrement = field
+		}
+	}
+	return meta
+}
+
+// Field describes a struct field mapped to a column.
+type Field struct {
+	Name          string // column name, or the field name for untagged fields
+	Quoted        string // quoted column name, empty for untagged fields
+	Index         []int  // field index path, including embedded and inline structs
+	Tagged        bool   // whether the field has a `db` tag name or is named by the name mapper
+	PK            bool
+	ReadOnly      bool
+	OmitEmpty     bool
+	Default       bool
+	AutoIncrement bool
+	SoftDelete    bool
+}
+
+// Metadata holds the precomputed column mapping of a struct type.
+type Metadata struct {
+	Fields        []Field  // all mapped fields in declaration order
+	Columns       []string // quoted column names of the tagged fields
+	Keys          []string // column names of the pk fields
+	SoftDelete    string   // column name of the softdelete field
+	AutoIncrement *Field   // the autoincrement field
+	tagged        map[string]int
+	loose         map[string]int
+}
+
+// Lookup returns the field mapped to the column.
+// Columns match the `db` tag or mapped name, or the untagged field name ignoring case and underscores.
+func (m *Metadata) Lookup(column string) (*Field, bool) {
+	idx, ok := m.tagged[column]
+	if !ok {
+		idx, ok = m.loose[normalize(column)]
+	}
+
+	if !ok {
+		return nil, false
+	}
+	return &m.Fields[idx], true
+}
+
+// Resolve extracts the quoted column names and values of the tagged fields of val for the given mode.
+// Columns listed in `exclude`, or missing from a non-empty `only` list, are skipped.
+// In bulk mode, fields resolved to DEFAULT are returned as Default.
+func (m *Metadata) Resolve(val reflect.Value, mode Mode, only, exclude []string) ([]string, []any) {
+	columns := make([]string, 0, len(m.Columns))
+	values := make([]any, 0, len(m.Columns))
+	for i := range m.Fields {
+		field := &m.Fields[i]
+		if !field.Tagged || skipped(field.Name, only, exclude) {
+			continue
+		}
+
+		value := val.FieldByIndex(field.Index)
+		included, defaulted := field.resolve(value, mode)
+		if !included {
+			continue
+		}
+
+		columns = append(columns, field.Quoted)
+		if defaulted {
+			values = append(values, Default{})
+		} else {
+			values = append(values, value.Interface())
+		}
+	}
+	return columns, values
+}
+
+// Pointers returns pointers to the fields of the addressable val matching the given columns, in order.
+func (m *Metadata) Pointers(val reflect.Value, columns []string) ([]any, error) {
+	pointers := make([]any, 0, len(columns))
+	for _, col := range columns {
+		field, ok := m.Lookup(col)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, col)
+		}
+		pointers = append(pointers, val.FieldByIndex(field.Index).Addr().Interface())
+	}
+	return pointers, nil
+}
+
+// resolve reports whether the field is included in the mode,
+// and whether its value should be replaced with DEFAULT.
+func (f *Field) resolve(value reflect.Value, mode Mode) (bool, bool) {
+	if mode == Select {
+		return true, false
+	}
+
+	if f.ReadOnly {
+		return false, false
+	}
+
+	zero := value.IsZero()
+	omitted := zero && f.OmitEmpty
+	defaulted := zero && f.Default
+	switch mode {
+	case Insert:
+		return !omitted && !defaulted, false
+	case Update:
+		return !omitted && !f.PK, false
+	default:
+		return true, omitted || defaulted
+	}
+}
+
+// appendFields appends the mapped fields of a struct type, skipping unexported, "-" tagged and relation fields.
+// Embedded structs are flattened, and struct fields tagged with the `inline` option are expanded
+// with the optional `prefix=` option prepended to their column names.
+// Untagged fields are named by `names` if it is not nil.
+func appendFields(fields []Field, typ reflect.Type, prefix string, index []int, names func(string) string) []Field {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		// Skip unexported fields, embedded structs may still promote exported fields.
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		tag, tagged := field.Tag.Lookup("db")
+		name, opts := parseTag(tag)
+		if _, related := field.Tag.Lookup("rel"); name == "-" || related {
+			continue
+		}
+
+		path := append(append(make([]int, 0, len(index)+1), index...), i)
+
+		// Flatten embedded and inline structs.
+		if field.Type.Kind() == reflect.Struct && (field.Anonymous || slices.Contains(opts, "inline")) {
+			fields = appendFields(fields, field.Type, prefix+option(opts, "prefix"), path, names)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if !tagged || name == "" {
+			// Struct fields may be relations loaded with an explicit Relation, only values are mapped
+			if names == nil || !isValue(field.Type) {
+				fields = append(fields, Field{Name: prefix + field.Name, Index: path})
+				continue
+			}
+			name = names(field.Name)
+		}
+
+		fields = append(fields, Field{
+			Name:          prefix + name,
+			Index:         path,
+			Tagged:        true,
+			PK:            slices.Contains(opts, "pk"),
+			ReadOnly:      slices.Contains(opts, "readonly"),
+			OmitEmpty:     slices.Contains(opts, "omitempty"),
+			Default:       slices.Contains(opts, "default"),
+			AutoIncrement: slices.Contains(opts, "autoincrement"),
+			SoftDelete:    slices.Contains(opts, "softdelete"),
+		})
+	}
+	return fields
+}
+
+// isValue reports whether an untagged field of type typ holds a column value that a name mapper may map,
+// excluding structs and slices of structs other than time.Time and sql.Scanner or driver.Valuer types.
+func isValue(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+		if reflect.PointerTo(typ).Implements(scannerType) || typ.Implements(valuerType) {
+			return true
+		}
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct || typ == timeType {
+		return true
+	}
+	return reflect.PointerTo(typ).Implements(scannerType) || typ.Implements(valuerType)
+}
+
+// parseTag splits a `db` struct tag into the column name and its comma separated options.
+func parseTag(tag string) (string, []string) {
+	name, rest, found := strings.Cut(tag, ",")
+	if !found {
+		return name, nil
+	}
+	return name, strings.Split(rest, ",")
+}
+
+// option returns the value of a `key=value` tag option, or an empty string.
+func option(opts []string, key string) string {
+	for _, opt := range opts {
+		if value, found := strings.CutPrefix(opt, key+"="); found {
+			return value
+		}
+	}
+	return ""
+}
+
+// normalize lowercases a name and removes underscores for loose column matching.
+func normalize(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// skipped checks if a column should be skipped based on `only` and `exclude` lists.
+func skipped(name string, only, exclude []string) bool {
+	return (len(only) > 0 && !slices.Contains(only, name)) ||
+		(len(exclude) > 0 && slices.Contains(exclude, name))
+}
@@ -0,0 +1,258 @@
+package mapper_test
+
+import (
+	"database/sql"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mekramy/gosql/internal/mapper"
+)
+
+type Timestamps struct {
+	CreatedAt time.Time `db:"created_at,default"`
+	UpdatedAt time.Time `db:"updated_at,omitempty"`
+}
+
+type Address struct {
+	Street string `db:"street"`
+	City   string `db:"city"`
+}
+
+type User struct {
+	Timestamps
+	Id       int     `db:"id,pk,readonly"`
+	Name     string  `db:"name"`
+	Address  Address `db:"address,inline,prefix=addr_"`
+	Nickname string
+	Ignored  string `db:"-"`
+	secret   string
+}
+
+func quote(name string) string {
+	return `"` + name + `"`
+}
+
+func TestMapper_Of(t *testing.T) {
+	meta := mapper.New(quote, nil).Of(reflect.TypeFor[User]())
+
+	expected := []string{`"created_at"`, `"updated_at"`, `"id"`, `"name"`, `"addr_street"`, `"addr_city"`}
+	if !slices.Equal(meta.Columns, expected) {
+		t.Fatalf("expected %v, got %v", expected, meta.Columns)
+	}
+
+	if !slices.Equal(meta.Keys, []string{"id"}) {
+		t.Fatalf("expected [id] keys, got %v", meta.Keys)
+	}
+
+	if field, ok := meta.Lookup("nick_name"); !ok || field.Name != "Nickname" {
+		t.Fatalf("expected untagged Nickname field, got %v", field)
+	}
+
+	if meta := mapper.New(quote, nil).Of(reflect.TypeFor[int]()); meta != nil {
+		t.Fatalf("expected nil metadata for non struct type")
+	}
+}
+
+func TestMetadata_Resolve(t *testing.T) {
+	meta := mapper.New(quote, nil).Of(reflect.TypeFor[User]())
+	user := User{Id: 1, Name: "John", Address: Address{City: "Paris"}}
+	val := reflect.ValueOf(user)
+
+	columns, values := meta.Resolve(val, mapper.Insert, nil, nil)
+	expected := []string{`"name"`, `"addr_street"`, `"addr_city"`}
+	if !slices.Equal(columns, expected) || len(values) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, columns)
+	}
+
+	columns, _ = meta.Resolve(val, mapper.Update, nil, []string{"addr_street"})
+	expected = []string{`"created_at"`, `"name"`, `"addr_city"`}
+	if !slices.Equal(columns, expected) {
+		t.Fatalf("expected %v, got %v", expected, columns)
+	}
+
+	_, values = meta.Resolve(val, mapper.Bulk, []string{"created_at", "name"}, nil)
+	if _, ok := values[0].(mapper.Default); !ok || values[1] != "John" {
+		t.Fatalf("expected [Default John], got %v", values)
+	}
+}
+
+func TestMetadata_Pointers(t *testing.T) {
+	meta := mapper.New(quote, nil).Of(reflect.TypeFor[User]())
+
+	var user User
+	val := reflect.ValueOf(&user).Elem()
+	pointers, err := meta.Pointers(val, []string{"id", "addr_city", "nickname"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	*(pointers[0].(*int)) = 7
+	*(pointers[1].(*string)) = "Paris"
+	if user.Id != 7 || user.Address.City != "Paris" {
+		t.Fatalf("expected pointers to struct fields, got %v", user)
+	}
+
+	if _, err := meta.Pointers(val, []string{"unknown"}); err == nil {
+		t.Fatal("expected unknown column error")
+	}
+}
+
+func TestMapper_Names(t *testing.T) {
+	meta := mapper.New(quote, mapper.SnakeCase).Of(reflect.TypeFor[User]())
+
+	columns, _ := meta.Resolve(reflect.ValueOf(User{Name: "John"}), mapper.Insert, nil, nil)
+	expected := []string{`"name"`, `"addr_street"`, `"addr_city"`, `"nickname"`}
+	if !slices.Equal(columns, expected) {
+		t.Fatalf("expected %v, got %v", expected, columns)
+	}
+
+	if field, ok := meta.Lookup("nickname"); !ok || !field.Tagged {
+		t.Fatalf("expected mapped nickname field, got %v", field)
+	}
+}
+
+func TestMapper_NamesRelations(t *testing.T) {
+	type Role struct {
+		Id     int    `db:"id,pk"`
+		UserId int    `db:"user_id"`
+		Title  string `db:"title"`
+	}
+	type Member struct {
+		Id        int `db:"id,pk"`
+		FullName  string
+		Tags      []string
+		JoinedAt  time.Time
+		LeftAt    sql.NullTime
+		Roles     []Role // preloaded with an explicit relation
+		Mentor    *Role
+		Addresses []*Address
+	}
+
+	m := mapper.New(quote, mapper.SnakeCase)
+	meta := m.Of(reflect.TypeFor[Member]())
+	expected := []string{`"id"`, `"full_name"`, `"tags"`, `"joined_at"`, `"left_at"`}
+	if !slices.Equal(meta.Columns, expected) {
+		t.Fatalf("expected %v, got %v", expected, meta.Columns)
+	}
+
+	load := func(typ reflect.Type, table, column string, keys []any) ([]reflect.Value, error) {
+		if cols := m.Of(typ).Columns; !slices.Equal(cols, []string{`"id"`, `"user_id"`, `"title"`}) {
+			t.Fatalf("expected role columns, got %v", cols)
+		}
+
+		result := reflect.New(typ)
+		result.Elem().Set(reflect.ValueOf(Role{Id: 1, UserId: 1, Title: "admin"}))
+		return []reflect.Value{result}, nil
+	}
+
+	members := []Member{{Id: 1}}
+	relation := &mapper.Relation{Kind: mapper.HasMany, Table: "roles", ForeignKey: "user_id"}
+	if err := m.Preload(reflect.ValueOf(members), "Roles", relation, load); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(members[0].Roles) != 1 || members[0].Roles[0].Title != "admin" {
+		t.Fatalf("expected admin role, got %v", members[0].Roles)
+	}
+}
+
+func TestNaming(t *testing.T) {
+	cases := []struct {
+		name  string
+		snake string
+		camel string
+	}{
+		{"Name", "name", "name"},
+		{"CreatedAt", "created_at", "createdAt"},
+		{"UserID", "user_id", "userID"},
+		{"HTTPServer", "http_server", "httpServer"},
+		{"ID", "id", "id"},
+		{"Address2Line", "address2_line", "address2Line"},
+	}
+
+	for _, c := range cases {
+		if got := mapper.SnakeCase(c.name); got != c.snake {
+			t.Fatalf("expected %s snake case, got %s", c.snake, got)
+		}
+
+		if got := mapper.CamelCase(c.name); got != c.camel {
+			t.Fatalf("expected %s camel case, got %s", c.camel, got)
+		}
+	}
+}
+
+func BenchmarkMetadata_Resolve(b *testing.B) {
+	m := mapper.New(quote, nil)
+	val := reflect.ValueOf(User{Id: 1, Name: "John"})
+
+	b.ReportAllocs()
+	for b.Loop() {
+		m.Of(val.Type()).Resolve(val, mapper.Insert, nil, nil)
+	}
+}
+
+func BenchmarkMetadata_Pointers(b *testing.B) {
+	m := mapper.New(quote, nil)
+	columns := []string{"id", "name", "created_at", "addr_city"}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		var user User
+		val := reflect.ValueOf(&user).Elem()
+		m.Of(val.Type()).Pointers(val, columns)
+	}
+}
+
+func TestMapper_Preload(t *testing.T) {
+	type Role struct {
+		Id     int    `db:"id,pk"`
+		UserId int64  `db:"user_id"`
+		Title  string `db:"title"`
+	}
+	type Member struct {
+		Id     int     `db:"id,pk"`
+		RoleId int     `db:"role_id"`
+		Roles  []Role  `rel:"has_many,table=roles,fk=user_id"`
+		Role   *Role   `rel:"belongs_to,table=roles,fk=role_id"`
+		Extra  *string `db:"-"`
+	}
+
+	roles := []Role{{Id: 1, UserId: 1, Title: "admin"}, {Id: 2, UserId: 1, Title: "editor"}, {Id: 3, UserId: 2, Title: "viewer"}}
+	load := func(typ reflect.Type, table, column string, keys []any) ([]reflect.Value, error) {
+		results := make([]reflect.Value, 0)
+		for _, role := range roles {
+			for _, key := range keys {
+				if (column == "user_id" && int64(key.(int)) == role.UserId) || (column == "id" && key.(int) == role.Id) {
+					result := reflect.New(typ)
+					result.Elem().Set(reflect.ValueOf(role))
+					results = append(results, result)
+				}
+			}
+		}
+		return results, nil
+	}
+
+	m := mapper.New(quote, nil)
+	members := []*Member{{Id: 1, RoleId: 3}, {Id: 2, RoleId: 1}, {Id: 3}}
+	if err := m.Preload(reflect.ValueOf(members), "Roles", nil, load); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := m.Preload(reflect.ValueOf(members), "Role", nil, load); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(members[0].Roles) != 2 || len(members[1].Roles) != 1 || members[2].Roles != nil {
+		t.Fatalf("expected [2 1 0] roles, got %v", members)
+	}
+
+	if members[0].Role == nil || members[0].Role.Title != "viewer" || members[2].Role != nil {
+		t.Fatalf("expected viewer role, got %v", members[0].Role)
+	}
+
+	if meta := m.Of(reflect.TypeFor[Member]()); len(meta.Columns) != 2 {
+		t.Fatalf("expected relation fields to be skipped, got %v", meta.Columns)
+	}
+}
@@ -0,0 +1,42 @@
+package mapper
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SnakeCase converts a field name to snake_case (e.g. UserID to user_id, HTTPServer to http_server).
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	builder.Grow(len(name) + 4)
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			next := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && next) {
+				builder.WriteByte('_')
+			}
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
+
+// CamelCase converts a field name to camelCase (e.g. UserID to userID, HTTPServer to httpServer).
+func CamelCase(name string) string {
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsUpper(r) {
+			break
+		}
+
+		// Keep the last upper letter of a leading acronym followed by a lowercase word.
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(r)
+	}
+	return string(runes)
+}
@@ -0,0 +1,224 @@
+package mapper
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrInvalidRelation is returned when a preloaded relation is not described correctly.
+var ErrInvalidRelation = errors.New("invalid relation")
+
+// RelationKind specifies how the records of a relation are linked.
+type RelationKind int
+
+const (
+	HasMany   RelationKind = iota // children reference the record key, loaded into a slice field
+	HasOne                        // a child references the record key, loaded into a struct or pointer field
+	BelongsTo                     // the record references the parent key, loaded into a struct or pointer field
+)
+
+// Relation describes the related table of a struct field.
+type Relation struct {
+	Kind       RelationKind
+	Table      string // related table name
+	ForeignKey string // referencing column, on the related table for HasMany and HasOne, on the record for BelongsTo
+	References string // referenced column, defaults to the single pk column or "id"
+}
+
+// Loader loads the records of the related table whose `column` matches one of `keys`.
+// Results must be pointers to new values of the `typ` struct type.
+type Loader func(typ reflect.Type, table, column string, keys []any) ([]reflect.Value, error)
+
+// ParseRelation parses a `rel` struct tag (e.g. `rel:"has_many,table=roles,fk=user_id,ref=id"`).
+func ParseRelation(tag string) (Relation, error) {
+	kind, opts := parseTag(tag)
+
+	relation := Relation{
+		Table:      option(opts, "table"),
+		ForeignKey: option(opts, "fk"),
+		References: option(opts, "ref"),
+	}
+
+	switch kind {
+	case "has_many":
+		relation.Kind = HasMany
+	case "has_one":
+		relation.Kind = HasOne
+	case "belongs_to":
+		relation.Kind = BelongsTo
+	default:
+		return relation, fmt.Errorf("%w: unknown kind %q", ErrInvalidRelation, kind)
+	}
+	return relation, nil
+}
+
+// Preload loads the `name` field relation of the records in the slice `records`
+// (of structs or struct pointers) with a single call to `load`, and attaches the results by key.
+// If relation is nil, the relation is parsed from the `rel` tag of the field.
+func (m *Mapper) Preload(records reflect.Value, name string, relation *Relation, load Loader) error {
+	typ := records.Type().Elem()
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	field, ok := typ.FieldByName(name)
+	if !ok || typ.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: unknown field %s", ErrInvalidRelation, name)
+	}
+
+	if relation == nil {
+		parsed, err := ParseRelation(field.Tag.Get("rel"))
+		if err != nil {
+			return fmt.Errorf("%w (%s)", err, name)
+		}
+		relation = &parsed
+	}
+
+	if relation.Table == "" || relation.ForeignKey == "" {
+		return fmt.Errorf("%w: table and foreign key are required (%s)", ErrInvalidRelation, name)
+	}
+
+	// Resolve the related struct type from []C, []*C, *C or C fields
+	target := field.Type
+	if target.Kind() == reflect.Slice {
+		if relation.Kind != HasMany {
+			return fmt.Errorf("%w: slice field requires has_many (%s)", ErrInvalidRelation, name)
+		}
+		target = target.Elem()
+	} else if relation.Kind == HasMany {
+		return fmt.Errorf("%w: has_many requires a slice field (%s)", ErrInvalidRelation, name)
+	}
+
+	related := target
+	if related.Kind() == reflect.Pointer {
+		related = related.Elem()
+	}
+
+	if related.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: expected struct relation (%s)", ErrInvalidRelation, name)
+	}
+
+	// Resolve the record key column and the matching related column
+	meta, relatedMeta := m.Of(typ), m.Of(related)
+	local, remote := relation.References, relation.ForeignKey
+	if relation.Kind == BelongsTo {
+		local, remote = relation.ForeignKey, relation.References
+		if remote == "" {
+			remote = relatedMeta.key()
+		}
+	} else if local == "" {
+		local = meta.key()
+	}
+
+	localField, ok := meta.Lookup(local)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownColumn, local)
+	}
+
+	remoteField, ok := relatedMeta.Lookup(remote)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownColumn, remote)
+	}
+
+	// Collect unique keys of the records
+	keys := make([]any, 0, records.Len())
+	seen := make(map[string]struct{}, records.Len())
+	for i := 0; i < records.Len(); i++ {
+		record := reflect.Indirect(records.Index(i))
+		if !record.IsValid() {
+			continue
+		}
+
+		arg, key, ok := relationKey(record.FieldByIndex(localField.Index))
+		if _, exists := seen[key]; ok && !exists {
+			seen[key] = struct{}{}
+			keys = append(keys, arg)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	results, err := load(related, relation.Table, remote, keys)
+	if err != nil {
+		return err
+	}
+
+	// Group related records by key and attach them
+	groups := make(map[string][]reflect.Value, len(keys))
+	for _, result := range results {
+		if _, key, ok := relationKey(result.Elem().FieldByIndex(remoteField.Index)); ok {
+			groups[key] = append(groups[key], result)
+		}
+	}
+
+	for i := 0; i < records.Len(); i++ {
+		record := reflect.Indirect(records.Index(i))
+		if !record.IsValid() {
+			continue
+		}
+
+		_, key, ok := relationKey(record.FieldByIndex(localField.Index))
+		if !ok || len(groups[key]) == 0 {
+			continue
+		}
+
+		dest := record.FieldByIndex(field.Index)
+		switch {
+		case dest.Kind() == reflect.Slice:
+			slice := reflect.MakeSlice(dest.Type(), 0, len(groups[key]))
+			for _, result := range groups[key] {
+				slice = reflect.Append(slice, assignable(result, target))
+			}
+			dest.Set(slice)
+		default:
+			dest.Set(assignable(groups[key][0], target))
+		}
+	}
+	return nil
+}
+
+// key returns the single pk column of the struct, or "id".
+func (m *Metadata) key() string {
+	if len(m.Keys) == 1 {
+		return m.Keys[0]
+	}
+	return "id"
+}
+
+// relationKey resolves the query argument and the comparable key of a relation column value.
+// Pointers are dereferenced and driver.Valuer values are resolved, so that keys of
+// different Go types (e.g. int and sql.NullInt64) match by value. Nil values are reported as invalid.
+func relationKey(value reflect.Value) (any, string, bool) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, "", false
+		}
+		value = value.Elem()
+	}
+
+	arg := value.Interface()
+	if valuer, ok := arg.(driver.Valuer); ok {
+		resolved, err := valuer.Value()
+		if err != nil || resolved == nil {
+			return nil, "", false
+		}
+		arg = resolved
+	}
+
+	if b, ok := arg.([]byte); ok {
+		return arg, string(b), true
+	}
+	return arg, fmt.Sprint(arg), true
+}
+
+// assignable converts a pointer to a related record into the type of the relation field.
+func assignable(result reflect.Value, target reflect.Type) reflect.Value {
+	if target.Kind() == reflect.Pointer {
+		return result
+	}
+	return result.Elem()
+}
@@ -0,0 +1,354 @@
+// Package pool keeps named connections open for the postgres and mysql connection managers.
+package pool
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned when the pool is used after Close.
+var ErrClosed = errors.New("connection pool is closed")
+
+// Closer is a closable connection.
+type Closer interface {
+	Close() error
+}
+
+// Opener opens the connection of the given name.
+type Opener[C Closer] func(ctx context.Context, name string) (C, error)
+
+// New creates a new pool that opens connections with open.
+// At most max connections are kept open by evicting the least recently used ones, zero means no limit.
+// Connections unused for idle are evicted by Watch, zero disables idle eviction.
+func New[C Closer](open Opener[C], max int, idle time.Duration) *Pool[C] {
+	return &Pool[C]{
+		open:    open,
+		max:     max,
+		idle:    idle,
+		entries: make(map[string]*list.Element),
+		recency: list.New(),
+		calls:   make(map[string]*call[C]),
+		stop:    make(chan struct{}),
+	}
+}
+
+// Pool holds named connections in the least recently used order.
+type Pool[C Closer] struct {
+	open    Opener[C]
+	max     int
+	idle    time.Duration
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	recency *list.List // most recently used first
+	calls   map[string]*call[C]
+	closed  bool
+	stop    chan struct{}
+	once    sync.Once
+	wg      sync.WaitGroup
+	closing sync.WaitGroup // evicted connections being closed
+}
+
+type entry[C Closer] struct {
+	name string
+	conn C
+	used time.Time
+}
+
+// call is an in flight open shared by concurrent Resolve calls.
+type call[C Closer] struct {
+	done chan struct{}
+	conn C
+	err  error
+}
+
+// touch marks the entry as used and returns its connection.
+func (p *Pool[C]) touch(elem *list.Element) C {
+	e := elem.Value.(*entry[C])
+	e.used = time.Now()
+	p.recency.MoveToFront(elem)
+	return e.conn
+}
+
+// store adds the connection and releases the connections evicted to stay within the limit.
+// The caller must hold the lock.
+func (p *Pool[C]) store(name string, conn C) {
+	p.entries[name] = p.recency.PushFront(&entry[C]{name: name, conn: conn, used: time.Now()})
+
+	for p.max > 0 && p.recency.Len() > p.max {
+		p.release(p.unlink(p.recency.Back()))
+	}
+}
+
+// release closes the evicted connection in the background, since closing may block
+// until its connections in use are returned. Eviction has no caller to report errors to.
+// The caller must hold the lock, and Close waits for the released connections.
+func (p *Pool[C]) release(conn C) {
+	p.closing.Add(1)
+	go func() {
+		defer p.closing.Done()
+		conn.Close()
+	}()
+}
+
+// unlink removes the entry and returns its connection.
+// The caller must hold the lock.
+func (p *Pool[C]) unlink(elem *list.Element) C {
+	e := p.recency.Remove(elem).(*entry[C])
+	delete(p.entries, e.name)
+	return e.conn
+}
+
+// remove closes and removes the connection of the given name.
+// The connection is kept if closing fails. The caller must hold the lock.
+func (p *Pool[C]) remove(name string) error {
+	if elem, exists := p.entries[name]; exists {
+		if err := elem.Value.(*entry[C]).conn.Close(); err != nil {
+			return err
+		}
+		p.unlink(elem)
+	}
+	return nil
+}
+
+// Add stores the connection, replacing and closing the existing one.
+// The connection is closed and ErrClosed returned if the pool is closed.
+func (p *Pool[C]) Add(name string, conn C) error {
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		conn.Close()
+		return ErrClosed
+	}
+
+	if err := p.remove(name); err != nil {
+		p.mutex.Unlock()
+		return err
+	}
+	p.store(name, conn)
+	p.mutex.Unlock()
+	return nil
+}
+
+// Connect opens a new connection, replacing and closing the existing one.
+func (p *Pool[C]) Connect(ctx context.Context, name string) error {
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return ErrClosed
+	}
+
+	if err := p.remove(name); err != nil {
+		p.mutex.Unlock()
+		return err
+	}
+	p.mutex.Unlock()
+
+	conn, err := p.open(ctx, name)
+	if err != nil {
+		return err
+	}
+	return p.Add(name, conn)
+}
+
+// Resolve returns the existing connection or opens a new one.
+// Concurrent calls for the same name share a single open, without holding the pool lock while opening.
+func (p *Pool[C]) Resolve(ctx context.Context, name string) (C, error) {
+	var zero C
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return zero, ErrClosed
+	}
+
+	if elem, exists := p.entries[name]; exists {
+		conn := p.touch(elem)
+		p.mutex.Unlock()
+		return conn, nil
+	}
+
+	// Wait for the in flight open
+	if c, exists := p.calls[name]; exists {
+		p.mutex.Unlock()
+		select {
+		case <-c.done:
+			return c.conn, c.err
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+
+	c := &call[C]{done: make(chan struct{})}
+	p.calls[name] = c
+	p.mutex.Unlock()
+
+	c.conn, c.err = p.open(ctx, name)
+
+	p.mutex.Lock()
+	delete(p.calls, name)
+	if c.err == nil {
+		if p.closed {
+			// Closed while opening, nothing else would close the connection.
+			// The deferred call is bound to the opened connection and runs after the lock is released.
+			defer c.conn.Close()
+			c.conn, c.err = zero, ErrClosed
+		} else if elem, exists := p.entries[name]; exists {
+			// Keep the connection added in the meantime
+			p.release(c.conn)
+			c.conn = p.touch(elem)
+		} else {
+			p.store(name, c.conn)
+		}
+	}
+	p.mutex.Unlock()
+	close(c.done)
+	return c.conn, c.err
+}
+
+// Get returns the connection of the given name and marks it as used.
+func (p *Pool[C]) Get(name string) (C, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if elem, exists := p.entries[name]; exists {
+		return p.touch(elem), true
+	}
+
+	var zero C
+	return zero, false
+}
+
+// Remove closes and removes the connection of the given name.
+func (p *Pool[C]) Remove(name string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.remove(name)
+}
+
+// Len returns the number of open connections.
+func (p *Pool[C]) Len() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.recency.Len()
+}
+
+// Range calls fn for each open connection, without holding the pool lock.
+func (p *Pool[C]) Range(fn func(name string, conn C)) {
+	p.mutex.Lock()
+	entries := make([]entry[C], 0, p.recency.Len())
+	for elem := p.recency.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, *elem.Value.(*entry[C]))
+	}
+	p.mutex.Unlock()
+
+	for _, e := range entries {
+		fn(e.name, e.conn)
+	}
+}
+
+// EvictIdle closes and removes the connections unused for the idle timeout.
+func (p *Pool[C]) EvictIdle() {
+	if p.idle <= 0 {
+		return
+	}
+
+	deadline := time.Now().Add(-p.idle)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	for elem := p.recency.Back(); elem != nil; elem = p.recency.Back() {
+		if elem.Value.(*entry[C]).used.After(deadline) {
+			break
+		}
+		p.release(p.unlink(elem))
+	}
+}
+
+// Watch evicts idle connections in the background until Close is called.
+func (p *Pool[C]) Watch() {
+	if p.idle <= 0 {
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(max(p.idle/2, time.Second))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.EvictIdle()
+			}
+		}
+	}()
+}
+
+// Monitor pings the connections every interval in the background until Close is called,
+// and reports health changes with a nil error on recovery. Each ping times out after the interval.
+func (p *Pool[C]) Monitor(interval time.Duration, ping func(context.Context, C) error, report func(name string, err error)) {
+	if interval <= 0 {
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		unhealthy := make(map[string]bool)
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				checked := make(map[string]bool)
+				p.Range(func(name string, conn C) {
+					ctx, cancel := context.WithTimeout(context.Background(), interval)
+					err := ping(ctx, conn)
+					cancel()
+
+					checked[name] = err != nil
+					if (err != nil) != unhealthy[name] {
+						report(name, err)
+					}
+				})
+				unhealthy = checked
+			}
+		}
+	}()
+}
+
+// Close stops the background tasks and closes all connections, including the evicted ones still closing.
+// Connections failing to close are kept and the last error is returned.
+// Connections opened after Close are closed and their Resolve returns ErrClosed.
+func (p *Pool[C]) Close() error {
+	p.once.Do(func() { close(p.stop) })
+	p.wg.Wait()
+
+	p.mutex.Lock()
+	p.closed = true
+	var finalErr error
+	for name := range p.entries {
+		if err := p.remove(name); err != nil {
+			finalErr = err
+		}
+	}
+	p.mutex.Unlock()
+
+	p.closing.Wait()
+	return finalErr
+}
@@ -0,0 +1,253 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mekramy/gosql/internal/pool"
+)
+
+type conn struct {
+	name   string
+	closed atomic.Bool
+}
+
+func (c *conn) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+func opener(opened *atomic.Int32) pool.Opener[*conn] {
+	return func(_ context.Context, name string) (*conn, error) {
+		opened.Add(1)
+		if name == "invalid" {
+			return nil, errors.New("invalid")
+		}
+		time.Sleep(10 * time.Millisecond)
+		return &conn{name: name}, nil
+	}
+}
+
+// eventually reports whether cond holds within a second.
+func eventually(cond func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPool_Resolve(t *testing.T) {
+	var opened atomic.Int32
+	p := pool.New(opener(&opened), 0, 0)
+	defer p.Close()
+
+	var wg sync.WaitGroup
+	results := make([]*conn, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, err := p.Resolve(context.Background(), "tenant")
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			results[i] = c
+		}()
+	}
+	wg.Wait()
+
+	if opened.Load() != 1 {
+		t.Fatalf("expected a single open, got %d", opened.Load())
+	}
+	for _, c := range results {
+		if c != results[0] {
+			t.Fatalf("expected the same connection for all callers")
+		}
+	}
+
+	if _, err := p.Resolve(context.Background(), "invalid"); err == nil {
+		t.Fatalf("expected open error")
+	}
+	if _, exists := p.Get("invalid"); exists {
+		t.Fatalf("expected failed connection to not be stored")
+	}
+}
+
+func TestPool_LRU(t *testing.T) {
+	var opened atomic.Int32
+	p := pool.New(opener(&opened), 2, 0)
+	defer p.Close()
+
+	ctx := context.Background()
+	a, _ := p.Resolve(ctx, "a")
+	b, _ := p.Resolve(ctx, "b")
+	p.Get("a") // b becomes the least recently used
+	p.Resolve(ctx, "c")
+
+	if p.Len() != 2 {
+		t.Fatalf("expected 2 connections, got %d", p.Len())
+	}
+	if _, exists := p.Get("b"); exists || !eventually(b.closed.Load) {
+		t.Fatalf("expected b to be evicted and closed")
+	}
+	if _, exists := p.Get("a"); !exists || a.closed.Load() {
+		t.Fatalf("expected a to be kept")
+	}
+}
+
+func TestPool_EvictIdle(t *testing.T) {
+	var opened atomic.Int32
+	p := pool.New(opener(&opened), 0, 20*time.Millisecond)
+	defer p.Close()
+
+	ctx := context.Background()
+	a, _ := p.Resolve(ctx, "a")
+	time.Sleep(30 * time.Millisecond)
+	p.Resolve(ctx, "b")
+	p.EvictIdle()
+
+	if _, exists := p.Get("a"); exists || !eventually(a.closed.Load) {
+		t.Fatalf("expected idle connection to be evicted")
+	}
+	if _, exists := p.Get("b"); !exists {
+		t.Fatalf("expected recent connection to be kept")
+	}
+}
+
+func TestPool_Close(t *testing.T) {
+	var opened atomic.Int32
+	p := pool.New(opener(&opened), 0, time.Second)
+	p.Watch()
+
+	a, _ := p.Resolve(context.Background(), "a")
+	if err := p.Close(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !a.closed.Load() || p.Len() != 0 {
+		t.Fatalf("expected all connections to be closed")
+	}
+}
+
+func TestPool_CloseWhileOpening(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var opened atomic.Pointer[conn]
+	p := pool.New(func(_ context.Context, name string) (*conn, error) {
+		close(started)
+		<-release
+		c := &conn{name: name}
+		opened.Store(c)
+		return c, nil
+	}, 0, 0)
+
+	errs := make(chan error)
+	go func() {
+		_, err := p.Resolve(context.Background(), "a")
+		errs <- err
+	}()
+
+	<-started
+	if err := p.Close(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	close(release)
+
+	if err := <-errs; !errors.Is(err, pool.ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	if c := opened.Load(); c == nil || !c.closed.Load() || p.Len() != 0 {
+		t.Fatalf("expected connection opened after Close to be closed")
+	}
+	if _, err := p.Resolve(context.Background(), "b"); !errors.Is(err, pool.ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+type blocking struct {
+	conn
+	release chan struct{}
+}
+
+func (b *blocking) Close() error {
+	<-b.release
+	return b.conn.Close()
+}
+
+func TestPool_BlockingEviction(t *testing.T) {
+	release := make(chan struct{})
+	p := pool.New(func(_ context.Context, name string) (*blocking, error) {
+		return &blocking{conn: conn{name: name}, release: release}, nil
+	}, 1, 0)
+
+	ctx := context.Background()
+	a, _ := p.Resolve(ctx, "a")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.Resolve(ctx, "b") // evicts a
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected eviction to not block on close")
+	}
+	if a.closed.Load() {
+		t.Fatalf("expected a to be closing")
+	}
+
+	close(release)
+	if err := p.Close(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !a.closed.Load() {
+		t.Fatalf("expected Close to wait for evicted connections")
+	}
+}
+
+type flaky struct {
+	conn
+	down atomic.Bool
+}
+
+func TestPool_Monitor(t *testing.T) {
+	f := &flaky{conn: conn{name: "a"}}
+	p := pool.New(func(context.Context, string) (*flaky, error) { return f, nil }, 0, 0)
+
+	reports := make(chan error, 10)
+	p.Monitor(
+		5*time.Millisecond,
+		func(_ context.Context, c *flaky) error {
+			if c.down.Load() {
+				return errors.New("down")
+			}
+			return nil
+		},
+		func(name string, err error) { reports <- err },
+	)
+	defer p.Close()
+
+	p.Resolve(context.Background(), "a")
+	f.down.Store(true)
+	if err := <-reports; err == nil {
+		t.Fatalf("expected unhealthy report")
+	}
+
+	f.down.Store(false)
+	if err := <-reports; err != nil {
+		t.Fatalf("expected recovery report, got %v", err)
+	}
+
+	select {
+	case err := <-reports:
+		t.Fatalf("expected reports on changes only, got %v", err)
+	case <-time.After(30 * time.Millisecond):
+	}
+}
@@ -0,0 +1,25 @@
+package pool
+
+import "time"
+
+// Stats describes the connections of a pool.
+type Stats struct {
+	Open             int           // number of established connections, both in use and idle
+	Idle             int           // number of idle connections
+	InUse            int           // number of connections currently in use
+	WaitCount        int64         // total number of acquires that waited for a connection
+	WaitDuration     time.Duration // total time spent acquiring connections
+	CanceledAcquires int64         // total number of acquires canceled by their context, always 0 with MySQL
+}
+
+// Add returns the sum of the stats.
+func (s Stats) Add(other Stats) Stats {
+	return Stats{
+		Open:             s.Open + other.Open,
+		Idle:             s.Idle + other.Idle,
+		InUse:            s.InUse + other.InUse,
+		WaitCount:        s.WaitCount + other.WaitCount,
+		WaitDuration:     s.WaitDuration + other.WaitDuration,
+		CanceledAcquires: s.CanceledAcquires + other.CanceledAcquires,
+	}
+}
@@ -0,0 +1,67 @@
+// Package retry runs operations again with jittered exponential backoff
+// for the retryable errors classified by the postgres and mysql packages.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy configures how an operation is retried.
+// Zero fields fall back to the defaults (3 attempts, 10ms base delay, 1s max delay).
+type Policy struct {
+	MaxAttempts int           // maximum number of attempts, including the first one
+	BaseDelay   time.Duration // delay before the second attempt, doubled for each attempt
+	MaxDelay    time.Duration // upper bound of the delay between attempts
+}
+
+// Do calls fn until it succeeds, returns a non retryable error, the attempts are exhausted
+// or the context is done. The last error is returned, joined with the context error if it is done.
+func Do(ctx context.Context, policy Policy, retryable func(error) bool, fn func() error) error {
+	policy = policy.normalize()
+
+	var err error
+	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(policy.delay(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return errors.Join(err, ctx.Err())
+			case <-timer.C:
+			}
+		}
+
+		if err = fn(); err == nil || !retryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// normalize applies the defaults to zero fields.
+func (p Policy) normalize() Policy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = 10 * time.Millisecond
+	}
+
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = time.Second
+	}
+	return p
+}
+
+// delay returns the jittered backoff before the given attempt, between half and the full exponential delay.
+func (p Policy) delay(attempt int) time.Duration {
+	delay := p.MaxDelay
+	if shift := attempt - 1; shift < 32 && p.BaseDelay<<shift < p.MaxDelay {
+		delay = p.BaseDelay << shift
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
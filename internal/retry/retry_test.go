@@ -0,0 +1,65 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mekramy/gosql/internal/retry"
+)
+
+var errRetryable = errors.New("retryable")
+
+func isRetryable(err error) bool {
+	return errors.Is(err, errRetryable)
+}
+
+func TestDo(t *testing.T) {
+	policy := retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+	attempts := 0
+	err := retry.Do(context.Background(), policy, isRetryable, func() error {
+		attempts++
+		if attempts < 3 {
+			return errRetryable
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Fatalf("expected success after 3 attempts, got %d, %v", attempts, err)
+	}
+
+	attempts = 0
+	err = retry.Do(context.Background(), policy, isRetryable, func() error {
+		attempts++
+		return errRetryable
+	})
+	if !errors.Is(err, errRetryable) || attempts != 3 {
+		t.Fatalf("expected retryable error after 3 attempts, got %d, %v", attempts, err)
+	}
+
+	attempts = 0
+	fatal := errors.New("fatal")
+	err = retry.Do(context.Background(), policy, isRetryable, func() error {
+		attempts++
+		return fatal
+	})
+	if !errors.Is(err, fatal) || attempts != 1 {
+		t.Fatalf("expected fatal error after 1 attempt, got %d, %v", attempts, err)
+	}
+}
+
+func TestDo_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	err := retry.Do(ctx, retry.Policy{MaxAttempts: 5}, isRetryable, func() error {
+		attempts++
+		return errRetryable
+	})
+	if !errors.Is(err, errRetryable) || attempts != 1 {
+		t.Fatalf("expected 1 attempt on canceled context, got %d, %v", attempts, err)
+	}
+}
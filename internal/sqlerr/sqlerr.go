@@ -0,0 +1,58 @@
+// Package sqlerr defines the errors shared by the postgres and mysql packages,
+// so callers can check them the same way whichever backend they use.
+package sqlerr
+
+import "errors"
+
+var (
+	ErrNotFound    = errors.New("no rows in result set")
+	ErrTooManyRows = errors.New("expected exactly one row, got more")
+)
+
+// Classified database errors, matched with errors.Is against an *Error.
+var (
+	ErrUniqueViolation     = errors.New("unique constraint violation")
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+	ErrNotNullViolation    = errors.New("not null constraint violation")
+	ErrCheckViolation      = errors.New("check constraint violation")
+	ErrDeadlock            = errors.New("deadlock detected")
+	ErrSerialization       = errors.New("serialization failure")
+	ErrLockTimeout         = errors.New("lock timeout")
+)
+
+// Error is a classified database error.
+// It matches its Kind with errors.Is, and the driver error with errors.Is and errors.As.
+type Error struct {
+	Kind       error  // one of the classified errors
+	Code       string // driver error code
+	Table      string // table name, if reported by the database
+	Column     string // column name, if reported by the database
+	Constraint string // constraint or index name, if reported by the database
+	Err        error  // driver error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Classify wraps err with the Error built by classify, unless err is nil, already classified,
+// or classify returns nil for unclassified errors.
+func Classify(err error, classify func(error) *Error) error {
+	if err == nil {
+		return nil
+	}
+
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	if e := classify(err); e != nil {
+		return e
+	}
+	return err
+}
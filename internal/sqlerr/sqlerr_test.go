@@ -0,0 +1,50 @@
+package sqlerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mekramy/gosql/internal/sqlerr"
+)
+
+type driverError struct{ code string }
+
+func (e *driverError) Error() string { return "driver error " + e.code }
+
+func classify(err error) *sqlerr.Error {
+	var d *driverError
+	if errors.As(err, &d) && d.code == "unique" {
+		return &sqlerr.Error{Kind: sqlerr.ErrUniqueViolation, Code: d.code, Constraint: "users_email_key", Err: err}
+	}
+	return nil
+}
+
+func TestClassify(t *testing.T) {
+	err := sqlerr.Classify(&driverError{code: "unique"}, classify)
+	if !errors.Is(err, sqlerr.ErrUniqueViolation) || errors.Is(err, sqlerr.ErrCheckViolation) {
+		t.Fatalf("expected unique violation, got %v", err)
+	}
+
+	var d *driverError
+	if !errors.As(err, &d) || err.Error() != "driver error unique" {
+		t.Fatalf("expected driver error to be preserved, got %v", err)
+	}
+
+	var classified *sqlerr.Error
+	if !errors.As(err, &classified) || classified.Constraint != "users_email_key" {
+		t.Fatalf("expected constraint name, got %v", classified)
+	}
+
+	if again := sqlerr.Classify(err, classify); again != err {
+		t.Fatalf("expected classified error to be returned as is")
+	}
+
+	other := &driverError{code: "other"}
+	if got := sqlerr.Classify(other, classify); got != other {
+		t.Fatalf("expected unclassified error to be returned as is, got %v", got)
+	}
+
+	if sqlerr.Classify(nil, classify) != nil {
+		t.Fatalf("expected nil")
+	}
+}
@@ -0,0 +1,93 @@
+// Package txhook tracks the commit and rollback callbacks of the transactions
+// started by the postgres and mysql connections.
+package txhook
+
+import "sync"
+
+// Hooks holds the callbacks registered within a transaction.
+type Hooks struct {
+	mu       sync.Mutex
+	commit   []func()
+	rollback []func()
+}
+
+// OnCommit registers fn to run after the transaction commits.
+func (h *Hooks) OnCommit(fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.commit = append(h.commit, fn)
+}
+
+// OnRollback registers fn to run after the transaction rolls back.
+func (h *Hooks) OnRollback(fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rollback = append(h.rollback, fn)
+}
+
+// Committed runs the commit callbacks, or moves all callbacks to the parent hooks
+// for nested transactions, since the enclosing transaction may still roll back.
+func (h *Hooks) Committed(parent *Hooks) {
+	h.mu.Lock()
+	commit, rollback := h.commit, h.rollback
+	h.commit, h.rollback = nil, nil
+	h.mu.Unlock()
+
+	if parent == nil {
+		run(commit)
+		return
+	}
+
+	parent.mu.Lock()
+	defer parent.mu.Unlock()
+	parent.commit = append(parent.commit, commit...)
+	parent.rollback = append(parent.rollback, rollback...)
+}
+
+// RolledBack runs the rollback callbacks and discards the commit callbacks.
+func (h *Hooks) RolledBack() {
+	h.mu.Lock()
+	rollback := h.rollback
+	h.commit, h.rollback = nil, nil
+	h.mu.Unlock()
+
+	run(rollback)
+}
+
+// Registry maps active transaction handles to their hooks.
+type Registry struct {
+	hooks sync.Map
+}
+
+// Register attaches new hooks to the transaction handle and returns them
+// with a function that restores the previously attached hooks, if any.
+func (r *Registry) Register(tx any) (*Hooks, func()) {
+	hooks := new(Hooks)
+	previous, loaded := r.hooks.Swap(tx, hooks)
+	return hooks, func() {
+		if loaded {
+			r.hooks.Store(tx, previous)
+		} else {
+			r.hooks.Delete(tx)
+		}
+	}
+}
+
+// Lookup returns the hooks of the transaction handle, or nil if it is not active.
+func (r *Registry) Lookup(tx any) *Hooks {
+	if tx == nil {
+		return nil
+	}
+
+	if hooks, ok := r.hooks.Load(tx); ok {
+		return hooks.(*Hooks)
+	}
+	return nil
+}
+
+// run calls the callbacks in registration order.
+func run(callbacks []func()) {
+	for _, fn := range callbacks {
+		fn()
+	}
+}
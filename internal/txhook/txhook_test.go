@@ -0,0 +1,40 @@
+package txhook_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mekramy/gosql/internal/txhook"
+)
+
+func TestRegistry(t *testing.T) {
+	var registry txhook.Registry
+	events := make([]string, 0)
+
+	outer, release := registry.Register("tx")
+	defer release()
+	outer.OnCommit(func() { events = append(events, "outer commit") })
+
+	// Nested unit sharing the same handle (e.g. a MySQL savepoint)
+	inner, restore := registry.Register("tx")
+	inner.OnCommit(func() { events = append(events, "inner commit") })
+	inner.OnRollback(func() { events = append(events, "inner rollback") })
+	inner.Committed(outer)
+	restore()
+
+	failed, restore := registry.Register("tx")
+	failed.OnCommit(func() { events = append(events, "failed commit") })
+	failed.OnRollback(func() { events = append(events, "failed rollback") })
+	failed.RolledBack()
+	restore()
+
+	if registry.Lookup("tx") != outer {
+		t.Fatal("expected outer hooks to be restored")
+	}
+
+	outer.Committed(nil)
+	expected := []string{"failed rollback", "outer commit", "inner commit"}
+	if !slices.Equal(events, expected) {
+		t.Fatalf("expected %v, got %v", expected, events)
+	}
+}
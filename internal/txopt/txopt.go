@@ -0,0 +1,21 @@
+// Package txopt defines the dialect-neutral transaction options shared by
+// the postgres, mysql and migration packages.
+package txopt
+
+// IsolationLevel specifies the transaction isolation level.
+type IsolationLevel int
+
+const (
+	Default         IsolationLevel = iota // database default isolation level
+	ReadUncommitted                       // READ UNCOMMITTED
+	ReadCommitted                         // READ COMMITTED
+	RepeatableRead                        // REPEATABLE READ
+	Serializable                          // SERIALIZABLE
+)
+
+// Options configures a transaction.
+type Options struct {
+	Isolation  IsolationLevel // isolation level, or the database default
+	ReadOnly   bool           // starts a read only transaction
+	Deferrable bool           // PostgreSQL only, defers serializable read only transactions until they can run safely
+}
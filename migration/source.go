@@ -3,6 +3,7 @@ package migration
 import (
 	"context"
 
+	"github.com/mekramy/gosql/internal/txopt"
 	"github.com/mekramy/gosql/mysql"
 	"github.com/mekramy/gosql/postgres"
 )
@@ -21,11 +22,16 @@ func NewPostgresSource(conn postgres.Connection) MigrationSource {
 	}
 }
 
+// TxOptions configures the isolation level and access mode of a migration transaction.
+// It is the same type as postgres.TxOptions and mysql.TxOptions.
+type TxOptions = txopt.Options
+
 // MigrationSource defines methods for running database migrations within a transaction.
 type MigrationSource interface {
 	// Transaction runs a function within a transaction context.
 	// The transaction is committed if the function succeeds, or rolled back in case of an error.
-	Transaction(ctx context.Context, callback func(ExecutableScanner) error) error
+	// Accepts optional TxOptions to set the isolation level and access mode.
+	Transaction(ctx context.Context, callback func(ExecutableScanner) error, options ...TxOptions) error
 
 	// Exec executes a SQL command with the provided arguments.
 	// Returns an error if the execution fails.
@@ -12,18 +12,10 @@ type mysqlSource struct {
 	conn mysql.Connection
 }
 
-func (ps *mysqlSource) Transaction(c context.Context, cb func(ExecutableScanner) error) error {
-	tx, err := ps.conn.Database().BeginTx(c, nil)
-	if err != nil {
-		return err
-	}
-
-	if err := cb(&mysqlTX{tx: tx}); err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	return tx.Commit()
+func (ps *mysqlSource) Transaction(c context.Context, cb func(ExecutableScanner) error, opts ...TxOptions) error {
+	return ps.conn.Transaction(c, func(tx *sql.Tx) error {
+		return cb(&mysqlTX{tx: tx})
+	}, opts...)
 }
 
 func (ps *mysqlSource) Exec(c context.Context, s string, args ...any) error {
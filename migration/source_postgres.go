@@ -12,18 +12,10 @@ type postgresSource struct {
 	conn postgres.Connection
 }
 
-func (ps *postgresSource) Transaction(c context.Context, cb func(ExecutableScanner) error) error {
-	tx, err := ps.conn.Database().Begin(c)
-	if err != nil {
-		return err
-	}
-
-	if err := cb(&postgresTx{tx: tx}); err != nil {
-		tx.Rollback(c)
-		return err
-	}
-
-	return tx.Commit(c)
+func (ps *postgresSource) Transaction(c context.Context, cb func(ExecutableScanner) error, opts ...TxOptions) error {
+	return ps.conn.Transaction(c, func(tx pgx.Tx) error {
+		return cb(&postgresTx{tx: tx})
+	}, opts...)
 }
 
 func (ps *postgresSource) Exec(c context.Context, s string, args ...any) error {
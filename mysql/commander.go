@@ -50,5 +50,6 @@ func (c *commander) Exec(ctx context.Context, args ...any) (sql.Result, error) {
 	}
 
 	cmd := compile(c.sql, c.replacements...)
-	return c.db.ExecContext(ctx, cmd, args...)
+	res, err := c.db.ExecContext(ctx, cmd, args...)
+	return res, classify(err)
 }
@@ -2,7 +2,11 @@ package mysql
 
 import (
 	"fmt"
+	"maps"
+	"net"
 	"net/url"
+	"os"
+	"strconv"
 	"strings"
 )
 
@@ -21,6 +25,57 @@ func NewConfig() Config {
 	}
 }
 
+// ParseConfig returns a Config parsed from a MySQL DSN ([user[:password]@][tcp(host:port)|unix(socket)]/database[?options]).
+// Missing host, port and user use the NewConfig defaults, while options are only those of the DSN.
+func ParseConfig(dsn string) (Config, error) {
+	c := NewConfig().(*config)
+	c.options = make(map[string]string)
+
+	if err := c.parseDSN(strings.TrimSpace(dsn)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// FromEnv returns a Config from the environment variables with the given prefix.
+// The prefix+"URL" variable is parsed with ParseConfig if set, then the HOST, PORT, USER,
+// PASSWORD, DATABASE and SOCKET variables override it (e.g. MYSQL_HOST with the "MYSQL_" prefix).
+// Without a URL, the NewConfig defaults are used for the unset variables.
+func FromEnv(prefix string) (Config, error) {
+	c := NewConfig()
+	if dsn, ok := os.LookupEnv(prefix + "URL"); ok {
+		parsed, err := ParseConfig(dsn)
+		if err != nil {
+			return nil, err
+		}
+		c = parsed
+	}
+
+	if v, ok := os.LookupEnv(prefix + "HOST"); ok {
+		c.Host(v)
+	}
+	if v, ok := os.LookupEnv(prefix + "PORT"); ok {
+		port, err := parsePort(v)
+		if err != nil {
+			return nil, err
+		}
+		c.Port(port)
+	}
+	if v, ok := os.LookupEnv(prefix + "USER"); ok {
+		c.User(v)
+	}
+	if v, ok := os.LookupEnv(prefix + "PASSWORD"); ok {
+		c.Password(v)
+	}
+	if v, ok := os.LookupEnv(prefix + "DATABASE"); ok {
+		c.Database(v)
+	}
+	if v, ok := os.LookupEnv(prefix + "SOCKET"); ok {
+		c.UnixSocket(v)
+	}
+	return c, nil
+}
+
 // Config defines methods for configuring MySQL connection parameters.
 type Config interface {
 	// Set the host for the MySQL connection.
@@ -56,6 +111,30 @@ type Config interface {
 	// Example: AddOption("tls", "true")
 	AddOption(key, value string) Config
 
+	// GetHost returns the host of the MySQL connection.
+	GetHost() string
+
+	// GetPort returns the port of the MySQL connection.
+	GetPort() int
+
+	// GetUser returns the user of the MySQL connection.
+	GetUser() string
+
+	// GetPassword returns the password of the MySQL connection.
+	GetPassword() string
+
+	// GetDatabase returns the database name of the MySQL connection.
+	GetDatabase() string
+
+	// GetUnixSocket returns the Unix socket of the MySQL connection, if any.
+	GetUnixSocket() string
+
+	// GetOption returns the value of a connection option (e.g. "charset", "parseTime").
+	GetOption(key string) (string, bool)
+
+	// GetOptions returns a copy of the connection options.
+	GetOptions() map[string]string
+
 	// Build the MySQL connection string based on the current configuration.
 	Build() string
 
@@ -124,6 +203,39 @@ func (c *config) AddOption(k, v string) Config {
 	return c
 }
 
+func (c *config) GetHost() string {
+	return c.host
+}
+
+func (c *config) GetPort() int {
+	return c.port
+}
+
+func (c *config) GetUser() string {
+	return c.user
+}
+
+func (c *config) GetPassword() string {
+	return c.password
+}
+
+func (c *config) GetDatabase() string {
+	return c.database
+}
+
+func (c *config) GetUnixSocket() string {
+	return c.socket
+}
+
+func (c *config) GetOption(k string) (string, bool) {
+	v, ok := c.options[k]
+	return v, ok
+}
+
+func (c *config) GetOptions() map[string]string {
+	return maps.Clone(c.options)
+}
+
 func (c *config) Build() string {
 	return c.buildFor(c.database)
 }
@@ -137,18 +249,19 @@ func (c *config) buildFor(db string) string {
 	}
 
 	// Determine connection type: Unix Socket or TCP.
-	var hostPort string
+	var address string
 	if c.socket != "" {
-		hostPort = fmt.Sprintf("unix(%s)", c.socket)
+		address = fmt.Sprintf("unix(%s)", c.socket)
 	} else {
-		hostPort = fmt.Sprintf("%s:%d", c.host, c.port)
+		address = fmt.Sprintf("tcp(%s)", net.JoinHostPort(c.host, strconv.Itoa(c.port)))
 	}
 
-	// Append the user, password, and host/port part.
-	sb.WriteString(fmt.Sprintf("%s:%s@tcp(%s)/%s",
-		url.QueryEscape(c.user),
-		url.QueryEscape(c.password),
-		hostPort,
+	// Append the user, password, and address part.
+	// The driver reads the credentials as is, up to the last '@'.
+	sb.WriteString(fmt.Sprintf("%s:%s@%s/%s",
+		c.user,
+		c.password,
+		address,
 		db))
 
 	// Append query parameters if options exist.
@@ -158,3 +271,71 @@ func (c *config) buildFor(db string) string {
 
 	return sb.String()
 }
+
+// parseDSN parses a MySQL DSN into the config.
+func (c *config) parseDSN(dsn string) error {
+	slash := strings.LastIndex(dsn, "/")
+	if slash < 0 {
+		return fmt.Errorf("%w: missing database separator", ErrInvalidDSN)
+	}
+
+	address := dsn[:slash]
+	database, query, _ := strings.Cut(dsn[slash+1:], "?")
+	c.database = database
+
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDSN, err)
+	}
+	for k := range params {
+		c.options[k] = params.Get(k)
+	}
+
+	// The password may contain '@', so the credentials end at the last one
+	if at := strings.LastIndex(address, "@"); at >= 0 {
+		c.user, c.password, _ = strings.Cut(address[:at], ":")
+		address = address[at+1:]
+	}
+
+	protocol, addr := address, ""
+	if open := strings.Index(address, "("); open >= 0 {
+		if !strings.HasSuffix(address, ")") {
+			return fmt.Errorf("%w: unclosed address %q", ErrInvalidDSN, address)
+		}
+		protocol, addr = address[:open], address[open+1:len(address)-1]
+	}
+
+	switch protocol {
+	case "", "tcp":
+		if addr == "" {
+			return nil
+		}
+
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			c.host = addr // host without port
+			return nil
+		}
+
+		if c.port, err = parsePort(port); err != nil {
+			return err
+		}
+		if host != "" {
+			c.host = host
+		}
+	case "unix":
+		c.socket = addr
+	default:
+		return fmt.Errorf("%w: unsupported protocol %q", ErrInvalidDSN, protocol)
+	}
+	return nil
+}
+
+// parsePort parses a port number in the valid range.
+func parsePort(v string) (int, error) {
+	port, err := strconv.Atoi(v)
+	if err != nil || port <= 0 || port >= 65536 {
+		return 0, fmt.Errorf("%w: invalid port %q", ErrInvalidDSN, v)
+	}
+	return port, nil
+}
@@ -5,8 +5,13 @@ import (
 	"database/sql"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/mekramy/gosql/internal/pool"
+	"github.com/mekramy/gosql/internal/retry"
 )
 
+// Stats describes the connections of a pool.
+type Stats = pool.Stats
+
 // ConfigModifier modifies database config before creation.
 type ConfigModifier func(*sql.DB)
 
@@ -34,12 +39,36 @@ type Connection interface {
 	// Database returns the underlying connection pool.
 	Database() *sql.DB
 
+	// Executor returns a Queryable that runs commands on the transaction stored in the context
+	// (see TransactionContext and WithTx), or on the pool otherwise.
+	// Repositories created with it stay transaction agnostic.
+	// It runs the per call hooks added with ContextWithHooks.
+	Executor() Queryable
+
 	// Ping verifies the database connection by sending a simple query.
 	Ping(ctx context.Context) error
 
+	// Stats returns the connection pool statistics.
+	// Acquire errors are not tracked by database/sql and always zero.
+	Stats() Stats
+
 	// Transaction executes a function within a transaction.
-	// Commits if successful, rolls back on error.
-	Transaction(ctx context.Context, cb func(*sql.Tx) error) error
+	// Commits if successful, rolls back on error or panic (the panic is propagated).
+	// If ctx carries an enclosing transaction (see WithTx), the function runs within a savepoint
+	// of that transaction instead, and only the inner unit is rolled back on error, options are ignored in this case.
+	// The callback only receives the transaction: calling Transaction again with the outer ctx starts an
+	// independent transaction, use TransactionContext or WithTx(ctx, tx) to nest transactions.
+	// The transaction doesn't carry the name mapper of a WithNameMapper wrapper, wrap it again if needed.
+	Transaction(ctx context.Context, cb func(*sql.Tx) error, options ...TxOptions) error
+
+	// TransactionContext executes a function within a transaction like Transaction,
+	// passing a context that carries the transaction for Executor and nested transactions.
+	TransactionContext(ctx context.Context, cb func(ctx context.Context) error, options ...TxOptions) error
+
+	// TransactionWithRetry executes a function within a transaction like Transaction, and re-runs the whole
+	// transaction with jittered backoff on deadlocks (1213) and lock wait timeouts (1205).
+	// Nested transactions are not retried, since the enclosing transaction must be retried as a whole.
+	TransactionWithRetry(ctx context.Context, cb func(*sql.Tx) error, policy RetryPolicy, options ...TxOptions) error
 
 	// Close terminates the database connection pool.
 	Close() error
@@ -53,22 +82,76 @@ func (d *mysqlConnection) Database() *sql.DB {
 	return d.db
 }
 
+func (d *mysqlConnection) Executor() Queryable {
+	return &hookedQueryable{db: &executor{db: d.db}}
+}
+
 func (d *mysqlConnection) Ping(ctx context.Context) error {
 	return d.db.PingContext(ctx)
 }
 
-func (d *mysqlConnection) Transaction(ctx context.Context, f func(*sql.Tx) error) error {
-	tx, err := d.db.BeginTx(ctx, nil)
+func (d *mysqlConnection) Stats() Stats {
+	stat := d.db.Stats()
+	return Stats{
+		Open:         stat.OpenConnections,
+		Idle:         stat.Idle,
+		InUse:        stat.InUse,
+		WaitCount:    stat.WaitCount,
+		WaitDuration: stat.WaitDuration,
+	}
+}
+
+func (d *mysqlConnection) Transaction(ctx context.Context, f func(*sql.Tx) error, opts ...TxOptions) error {
+	if parent := TxFromContext(ctx); parent != nil {
+		return savepoint(ctx, parent, f)
+	}
+
+	tx, err := d.db.BeginTx(ctx, sqlTxOptions(parseVariadic(TxOptions{}, opts...)))
 	if err != nil {
-		return err
+		return classify(err)
 	}
 
+	hooks, release := transactions.Register(tx)
+	defer release()
+
+	// Roll back and re-panic if the callback panics
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			hooks.RolledBack()
+			panic(p)
+		}
+	}()
+
 	if err := f(tx); err != nil {
 		tx.Rollback()
-		return err
+		hooks.RolledBack()
+		return classify(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		hooks.RolledBack()
+		return classify(err)
+	}
+
+	hooks.Committed(nil)
+	return nil
+}
+
+func (d *mysqlConnection) TransactionContext(ctx context.Context, f func(context.Context) error, opts ...TxOptions) error {
+	return d.Transaction(ctx, func(tx *sql.Tx) error {
+		return f(WithTx(ctx, tx))
+	}, opts...)
+}
+
+func (d *mysqlConnection) TransactionWithRetry(ctx context.Context, f func(*sql.Tx) error, policy RetryPolicy, opts ...TxOptions) error {
+	if TxFromContext(ctx) != nil {
+		return d.Transaction(ctx, f, opts...)
 	}
 
-	return tx.Commit()
+	return retry.Do(ctx, policy, isRetryable, func() error {
+		return d.Transaction(ctx, f, opts...)
+	})
 }
 
 func (d *mysqlConnection) Close() error {
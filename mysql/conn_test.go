@@ -3,8 +3,12 @@ package mysql_test
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	driver "github.com/go-sql-driver/mysql"
 	"github.com/mekramy/gosql/mysql"
 )
 
@@ -22,6 +26,76 @@ func TestConfigBuildDSN(t *testing.T) {
 	}
 }
 
+func TestParseConfig(t *testing.T) {
+	config := mysql.NewConfig().
+		Host("db.example.com").
+		Port(3307).
+		User("app").
+		Password("p@ss:word").
+		Database("test").
+		Loc("America/New_York")
+
+	parsed, err := mysql.ParseConfig(config.Build())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if parsed.Build() != config.Build() {
+		t.Fatalf("expected %v, got %v", config.Build(), parsed.Build())
+	}
+
+	if parsed.GetHost() != "db.example.com" || parsed.GetPort() != 3307 || parsed.GetPassword() != "p@ss:word" {
+		t.Fatalf("unexpected parsed config %v", parsed.Build())
+	}
+
+	if loc, ok := parsed.GetOption("loc"); !ok || loc != "America/New_York" {
+		t.Fatalf("expected loc option, got %v", parsed.GetOptions())
+	}
+
+	socket, err := mysql.ParseConfig("root:root@unix(/var/run/mysqld/mysqld.sock)/test")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expectedDSN := "root:root@unix(/var/run/mysqld/mysqld.sock)/test"
+	if socket.GetUnixSocket() != "/var/run/mysqld/mysqld.sock" || socket.Build() != expectedDSN {
+		t.Fatalf("expected %v, got %v", expectedDSN, socket.Build())
+	}
+
+	for _, dsn := range []string{"localhost:3306", "root@tcp(localhost:invalid)/test", "root@pipe(name)/test"} {
+		if _, err := mysql.ParseConfig(dsn); !errors.Is(err, mysql.ErrInvalidDSN) {
+			t.Fatalf("expected invalid DSN error for %q, got %v", dsn, err)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("MYSQL_URL", "app:secret@tcp(db.example.com:3306)/test?parseTime=true")
+	t.Setenv("MYSQL_DATABASE", "reports")
+
+	config, err := mysql.FromEnv("MYSQL_")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expectedDSN := "app:secret@tcp(db.example.com:3306)/reports?parseTime=true"
+	if dsn := config.Build(); dsn != expectedDSN {
+		t.Fatalf("expected %v, got %v", expectedDSN, dsn)
+	}
+
+	t.Setenv("DB_HOST", "mysql.example.com")
+	t.Setenv("DB_PORT", "3307")
+
+	config, err = mysql.FromEnv("DB_")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if config.GetHost() != "mysql.example.com" || config.GetPort() != 3307 || config.GetUser() != "root" {
+		t.Fatalf("unexpected config %v", config.Build())
+	}
+}
+
 func TestNewConnection(t *testing.T) {
 	ctx := context.Background()
 	config := mysql.NewConfig().
@@ -45,6 +119,10 @@ func TestNewConnection(t *testing.T) {
 		t.Fatalf("expected no error, got %v", err)
 	}
 	defer conn.Close()
+
+	if stats := conn.Stats(); stats.Open < 1 || stats.Open != stats.Idle+stats.InUse {
+		t.Fatalf("expected open connections, got %+v", stats)
+	}
 }
 
 func TestConnectionManager(t *testing.T) {
@@ -76,6 +154,169 @@ func TestConnectionManager(t *testing.T) {
 	if exists {
 		t.Fatalf("expected connection to not exist")
 	}
+
+	t.Run("Health", func(t *testing.T) {
+		reports := make(chan error, 1)
+		manager := mysql.NewConnectionManager(
+			config,
+			mysql.MonitorHealth(10*time.Millisecond, func(name string, err error) { reports <- err }),
+		)
+		defer manager.Close()
+
+		if _, err := manager.Resolve(ctx, "test"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if stats := manager.Stats(); stats.Open < 1 {
+			t.Fatalf("expected open connections, got %+v", stats)
+		}
+
+		select {
+		case err := <-reports:
+			t.Fatalf("expected no health change, got %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		manager := mysql.NewConnectionManager(
+			config,
+			mysql.MaxPools(1),
+			mysql.IdleTimeout(time.Minute),
+			mysql.ResolveConfig(func(name string) (mysql.Config, error) {
+				return config, nil
+			}),
+		)
+		defer manager.Close()
+
+		if _, err := manager.Resolve(ctx, "test"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if _, err := manager.Resolve(ctx, "mysql"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if _, exists := manager.Get("test"); exists {
+			t.Fatalf("expected least recently used connection to be evicted")
+		}
+	})
+}
+
+func TestConnectionManagerErrors(t *testing.T) {
+	ctx := context.Background()
+	manager := mysql.NewConnectionManager(
+		mysql.NewConfig(),
+		mysql.ResolveConfig(func(name string) (mysql.Config, error) {
+			return nil, nil
+		}),
+	)
+
+	if _, err := manager.Resolve(ctx, "tenant"); !errors.Is(err, mysql.ErrNilConfig) {
+		t.Fatalf("expected ErrNilConfig, got %v", err)
+	}
+
+	if err := manager.Close(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := manager.Resolve(ctx, "tenant"); !errors.Is(err, mysql.ErrManagerClosed) {
+		t.Fatalf("expected ErrManagerClosed, got %v", err)
+	}
+}
+
+func TestCluster(t *testing.T) {
+	ctx := context.Background()
+	// Each node sets a distinct session wait_timeout to identify the connection serving a command
+	config := func(timeout string) string {
+		return mysql.NewConfig().
+			Host("localhost").
+			User("root").
+			Password("root").
+			Database("test").
+			AddOption("wait_timeout", timeout).
+			Build()
+	}
+
+	primary, err := mysql.New(ctx, config("1001"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	replica, err := mysql.New(ctx, config("1002"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	cluster := mysql.NewCluster(primary, []mysql.Connection{replica}, mysql.Balance(mysql.LeastConnections))
+	defer cluster.Close()
+
+	_, err = cluster.ExecContext(ctx, "DROP TABLE IF EXISTS cluster_nodes;")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = cluster.ExecContext(ctx, "CREATE TABLE cluster_nodes (id SERIAL PRIMARY KEY, label TEXT, node INT);")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// node returns the wait_timeout of the connection serving the read
+	node := func(ctx context.Context) int {
+		var timeout int
+		if err := cluster.QueryRowContext(ctx, "SELECT @@session.wait_timeout;").Scan(&timeout); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return timeout
+	}
+
+	if timeout := node(ctx); timeout != 1002 {
+		t.Fatalf("expected read on replica, got %d", timeout)
+	}
+
+	if timeout := node(mysql.ForcePrimary(ctx)); timeout != 1001 {
+		t.Fatalf("expected forced read on primary, got %d", timeout)
+	}
+
+	insert := "INSERT INTO cluster_nodes (label, node) VALUES (?, @@session.wait_timeout);"
+	if _, err := cluster.ExecContext(ctx, insert, "exec"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = cluster.TransactionContext(ctx, func(ctx context.Context) error {
+		if timeout := node(ctx); timeout != 1001 {
+			return fmt.Errorf("expected transaction read on primary, got %d", timeout)
+		}
+
+		_, err := cluster.ExecContext(ctx, insert, "transaction")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	type ClusterNode struct {
+		Id    int    `db:"id"`
+		Label string `db:"label"`
+		Node  int    `db:"node"`
+	}
+
+	nodes, err := mysql.NewFinder[ClusterNode](cluster).
+		Query("SELECT * FROM cluster_nodes ORDER BY id;").
+		Structs(mysql.ForcePrimary(ctx))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(nodes))
+	}
+
+	for _, n := range nodes {
+		if n.Node != 1001 {
+			t.Fatalf("expected %s write on primary, got %d", n.Label, n.Node)
+		}
+	}
 }
 
 func TestTransaction(t *testing.T) {
@@ -103,4 +344,115 @@ func TestTransaction(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
+
+	t.Run("Nested", func(t *testing.T) {
+		var count int64
+		err := conn.Transaction(ctx, func(tx *sql.Tx) error {
+			inner := mysql.WithTx(ctx, tx)
+			if _, err := tx.ExecContext(ctx, "DELETE FROM test WHERE name = ?", "nested"); err != nil {
+				return err
+			}
+
+			// Failed inner unit must only roll back its own changes
+			_ = conn.Transaction(inner, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, "INSERT INTO test (name) VALUES (?)", "nested"); err != nil {
+					return err
+				}
+				return errors.New("rollback inner")
+			})
+
+			err := conn.Transaction(inner, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, "INSERT INTO test (name) VALUES (?)", "nested")
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			count, err = mysql.NewCounter(tx).Query("SELECT COUNT(*) FROM test WHERE name = ?").Count(ctx, "nested")
+			return err
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if count != 1 {
+			t.Fatalf("expected 1 nested row, got %d", count)
+		}
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		attempts := 0
+		policy := mysql.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+		err := conn.TransactionWithRetry(ctx, func(tx *sql.Tx) error {
+			if attempts++; attempts < 2 {
+				return &driver.MySQLError{Number: 1213}
+			}
+			return nil
+		}, policy)
+		if err != nil || attempts != 2 {
+			t.Fatalf("expected success after 2 attempts, got %d, %v", attempts, err)
+		}
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		db := conn.Executor()
+		counter := mysql.NewCounter(db).Query("SELECT COUNT(*) FROM test WHERE name = ?")
+
+		err := conn.TransactionContext(ctx, func(ctx context.Context) error {
+			if _, err := mysql.NewCmd(db).Command("INSERT INTO test (name) VALUES (?)").Exec(ctx, "context"); err != nil {
+				return err
+			}
+
+			if count, err := counter.Count(ctx, "context"); err != nil || count != 1 {
+				return fmt.Errorf("expected 1 row inside transaction, got %d, %v", count, err)
+			}
+			return errors.New("rollback")
+		})
+		if err == nil || err.Error() != "rollback" {
+			t.Fatalf("expected rollback error, got %v", err)
+		}
+
+		if count, err := counter.Count(ctx, "context"); err != nil || count != 0 {
+			t.Fatalf("expected rolled back row, got %d, %v", count, err)
+		}
+	})
+
+	t.Run("Hooks", func(t *testing.T) {
+		committed, rolledBack := false, false
+		err := conn.Transaction(ctx, func(tx *sql.Tx) error {
+			return mysql.OnCommit(tx, func() { committed = true })
+		})
+		if err != nil || !committed {
+			t.Fatalf("expected commit hook to run, got %v", err)
+		}
+
+		func() {
+			defer func() {
+				if p := recover(); p == nil {
+					t.Fatal("expected panic to be propagated")
+				}
+			}()
+
+			conn.TransactionContext(ctx, func(ctx context.Context) error {
+				mysql.OnRollbackContext(ctx, func() { rolledBack = true })
+				panic("boom")
+			})
+		}()
+
+		if !rolledBack {
+			t.Fatal("expected rollback hook to run on panic")
+		}
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		options := mysql.TxOptions{Isolation: mysql.LevelSerializable, ReadOnly: true}
+		err := conn.Transaction(ctx, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "INSERT INTO test (name) VALUES (?)", "readonly")
+			return err
+		}, options)
+		if err == nil {
+			t.Fatal("expected read only transaction to reject insert")
+		}
+	})
 }
@@ -0,0 +1,108 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mekramy/gosql/internal/mapper"
+)
+
+// NewDeleter initializes and returns a new Deleter instance for the given Executable interface.
+// If T has a field tagged with the `softdelete` option (e.g. `db:"deleted_at,softdelete"`),
+// Delete marks records as deleted instead of removing them.
+func NewDeleter[T any](e Executable) Deleter[T] {
+	return &deleter[T]{
+		db:     e,
+		mapper: mapperOf(e),
+	}
+}
+
+// Deleter provides methods for deleting records from a specified database table.
+type Deleter[T any] interface {
+	// Table sets the target table for the delete operation.
+	Table(table string) Deleter[T]
+
+	// Where specifies the WHERE condition for the delete.
+	Where(condition string, args ...any) Deleter[T]
+
+	// Delete removes the matching records, or sets the soft delete column to the current time in soft delete mode.
+	// Records already soft deleted keep their original deletion time.
+	Delete(ctx context.Context) (sql.Result, error)
+
+	// Restore clears the soft delete column of the matching soft deleted records.
+	// Returns ErrNoSoftDelete if T has no soft delete column.
+	Restore(ctx context.Context) (sql.Result, error)
+
+	// ForceDelete removes the matching records, even in soft delete mode.
+	ForceDelete(ctx context.Context) (sql.Result, error)
+}
+
+type deleter[T any] struct {
+	db     Executable
+	mapper *mapper.Mapper
+	table  string
+	where  string
+	args   []any
+}
+
+func (d *deleter[T]) Table(t string) Deleter[T] {
+	d.table = t
+	return d
+}
+
+func (d *deleter[T]) Where(w string, args ...any) Deleter[T] {
+	d.where = w
+	d.args = append([]any{}, args...)
+	return d
+}
+
+func (d *deleter[T]) Delete(ctx context.Context) (sql.Result, error) {
+	if !isStruct[T]() {
+		return nil, ErrStructOnly
+	}
+
+	if column := softDeleteColumn[T](d.mapper); column != "" {
+		return d.exec(ctx, fmt.Sprintf("UPDATE %s SET %s = NOW()", quoteTable(d.table), quoteField(column)), quoteField(column)+" IS NULL")
+	}
+
+	return d.exec(ctx, fmt.Sprintf("DELETE FROM %s", quoteTable(d.table)), "")
+}
+
+func (d *deleter[T]) Restore(ctx context.Context) (sql.Result, error) {
+	if !isStruct[T]() {
+		return nil, ErrStructOnly
+	}
+
+	column := softDeleteColumn[T](d.mapper)
+	if column == "" {
+		return nil, ErrNoSoftDelete
+	}
+
+	return d.exec(ctx, fmt.Sprintf("UPDATE %s SET %s = NULL", quoteTable(d.table), quoteField(column)), quoteField(column)+" IS NOT NULL")
+}
+
+func (d *deleter[T]) ForceDelete(ctx context.Context) (sql.Result, error) {
+	if !isStruct[T]() {
+		return nil, ErrStructOnly
+	}
+
+	return d.exec(ctx, fmt.Sprintf("DELETE FROM %s", quoteTable(d.table)), "")
+}
+
+// exec appends the WHERE clause to the command and executes it.
+// A non empty guard is added to the WHERE condition, e.g. to skip already soft deleted records.
+func (d *deleter[T]) exec(ctx context.Context, cmd string, guard string) (sql.Result, error) {
+	if d.table == "" || d.where == "" {
+		return nil, ErrEmptySQL
+	}
+
+	where := d.where
+	if guard != "" {
+		where = fmt.Sprintf("(%s) AND %s", where, guard)
+	}
+
+	cmd = fmt.Sprintf("%s WHERE %s;", cmd, where)
+	res, err := d.db.ExecContext(ctx, cmd, d.args...)
+	return res, classify(err)
+}
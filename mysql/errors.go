@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/mekramy/gosql/internal/sqlerr"
+)
+
+// SQLError is a classified database error, matching one of the classified errors
+// (e.g. ErrUniqueViolation) and the underlying *mysql.MySQLError.
+// MySQL reports names in the error message only, so they are parsed from it.
+type SQLError = sqlerr.Error
+
+// errorKinds maps the MySQL error numbers to the classified errors.
+var errorKinds = map[uint16]error{
+	1062: sqlerr.ErrUniqueViolation,     // ER_DUP_ENTRY
+	1586: sqlerr.ErrUniqueViolation,     // ER_DUP_ENTRY_WITH_KEY_NAME
+	1216: sqlerr.ErrForeignKeyViolation, // ER_NO_REFERENCED_ROW
+	1217: sqlerr.ErrForeignKeyViolation, // ER_ROW_IS_REFERENCED
+	1451: sqlerr.ErrForeignKeyViolation, // ER_ROW_IS_REFERENCED_2
+	1452: sqlerr.ErrForeignKeyViolation, // ER_NO_REFERENCED_ROW_2
+	1048: sqlerr.ErrNotNullViolation,    // ER_BAD_NULL_ERROR
+	1364: sqlerr.ErrNotNullViolation,    // ER_NO_DEFAULT_FOR_FIELD
+	3819: sqlerr.ErrCheckViolation,      // ER_CHECK_CONSTRAINT_VIOLATED
+	1213: sqlerr.ErrDeadlock,            // ER_LOCK_DEADLOCK
+	1205: sqlerr.ErrLockTimeout,         // ER_LOCK_WAIT_TIMEOUT
+	3572: sqlerr.ErrLockTimeout,         // ER_LOCK_NOWAIT
+}
+
+var (
+	keyPattern        = regexp.MustCompile(`for key '([^']+)'`)
+	foreignKeyPattern = regexp.MustCompile("fails \\(`[^`]+`\\.`([^`]+)`, CONSTRAINT `([^`]+)` FOREIGN KEY \\(`([^`]+)`\\)")
+	columnPattern     = regexp.MustCompile(`^(?:Column|Field) '([^']+)'`)
+	checkPattern      = regexp.MustCompile(`^Check constraint '([^']+)'`)
+)
+
+// classify wraps the MySQL errors in a SQLError.
+func classify(err error) error {
+	return sqlerr.Classify(err, func(err error) *SQLError {
+		var myErr *driver.MySQLError
+		if !errors.As(err, &myErr) {
+			return nil
+		}
+
+		kind, ok := errorKinds[myErr.Number]
+		if !ok {
+			return nil
+		}
+
+		e := &SQLError{Kind: kind, Code: strconv.Itoa(int(myErr.Number)), Err: err}
+		if match := keyPattern.FindStringSubmatch(myErr.Message); match != nil {
+			// MySQL 8.0.19+ prefixes the key name with the table name
+			if table, key, found := strings.Cut(match[1], "."); found {
+				e.Table, e.Constraint = table, key
+			} else {
+				e.Constraint = match[1]
+			}
+		} else if match := foreignKeyPattern.FindStringSubmatch(myErr.Message); match != nil {
+			e.Table, e.Constraint, e.Column = match[1], match[2], match[3]
+		} else if match := columnPattern.FindStringSubmatch(myErr.Message); match != nil {
+			e.Column = match[1]
+		} else if match := checkPattern.FindStringSubmatch(myErr.Message); match != nil {
+			e.Constraint = match[1]
+		}
+		return e
+	})
+}
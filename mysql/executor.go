@@ -0,0 +1,31 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// executor runs commands on the transaction stored in the context, or on the pool otherwise.
+type executor struct {
+	db *sql.DB
+}
+
+// target returns the transaction stored in ctx, or the pool.
+func (e *executor) target(ctx context.Context) Queryable {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return e.db
+}
+
+func (e *executor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return e.target(ctx).ExecContext(ctx, query, args...)
+}
+
+func (e *executor) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return e.target(ctx).QueryContext(ctx, query, args...)
+}
+
+func (e *executor) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return e.target(ctx).QueryRowContext(ctx, query, args...)
+}
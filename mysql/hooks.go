@@ -0,0 +1,147 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/mekramy/gosql/internal/hook"
+	"github.com/mekramy/gosql/internal/mapper"
+)
+
+// Hook observes the database calls of a Queryable wrapped by WithHooks.
+type Hook = hook.Hook
+
+// QueryEvent describes an observed database call.
+type QueryEvent = hook.Event
+
+// Operation is the kind of database call observed by a hook.
+type Operation = hook.Operation
+
+const (
+	OpExec     = hook.OpExec     // ExecContext
+	OpQuery    = hook.OpQuery    // QueryContext, observed until builders consume the rows, or until the query returns when called directly (rows -1)
+	OpQueryRow = hook.OpQueryRow // QueryRowContext, observed until the query returns, rows are unknown (-1)
+)
+
+// WithHooks wraps the database (e.g. Connection.Executor(), *sql.DB, *sql.Tx) so that all builders
+// and repositories created with it run the hooks around each database call.
+// Hooks added to the call context with ContextWithHooks run after these hooks.
+func WithHooks(db Queryable, hooks ...Hook) Queryable {
+	if hooked, ok := db.(*hookedQueryable); ok {
+		return &hookedQueryable{
+			db:    hooked.db,
+			hooks: append(hooked.hooks[:len(hooked.hooks):len(hooked.hooks)], hooks...),
+		}
+	}
+	return &hookedQueryable{db: db, hooks: hooks}
+}
+
+// ContextWithHooks returns a copy of ctx carrying per call hooks, which run on the database calls
+// of Connection.Executor(), Cluster.Executor() and Queryables wrapped by WithHooks.
+// Pools and transactions used directly (e.g. Connection.Database(), the Transaction callback) don't run them.
+func ContextWithHooks(ctx context.Context, hooks ...Hook) context.Context {
+	return hook.WithContext(ctx, hooks...)
+}
+
+// SlowQueryLogger returns a hook logging the database calls that take at least threshold as warnings,
+// using slog.Default() if logger is nil. Arguments are redacted, only their count is logged.
+func SlowQueryLogger(logger *slog.Logger, threshold time.Duration) Hook {
+	return hook.SlowQueryLogger(logger, threshold)
+}
+
+type hookedQueryable struct {
+	db    Queryable
+	hooks []Hook
+}
+
+func (h *hookedQueryable) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, finish := hook.Start(ctx, h.hooks, OpExec, query, args)
+	result, err := h.db.ExecContext(ctx, query, args...)
+
+	affected := int64(-1)
+	if err == nil {
+		if n, err := result.RowsAffected(); err == nil {
+			affected = n
+		}
+	}
+	finish(affected, err)
+	return result, err
+}
+
+func (h *hookedQueryable) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, finish := hook.Start(ctx, h.hooks, OpQuery, query, args)
+	rows, err := h.db.QueryContext(ctx, query, args...)
+	finish(-1, err)
+	return rows, err
+}
+
+func (h *hookedQueryable) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, finish := hook.Start(ctx, h.hooks, OpQueryRow, query, args)
+	row := h.db.QueryRowContext(ctx, query, args...)
+	finish(-1, row.Err())
+	return row
+}
+
+// queryRows runs the query like QueryContext, but finishes the hooks once the rows are consumed or closed.
+func (h *hookedQueryable) queryRows(ctx context.Context, query string, args ...any) (*observedRows, error) {
+	ctx, finish := hook.Start(ctx, h.hooks, OpQuery, query, args)
+	rows, err := queryRows(ctx, h.db, query, args...)
+	if err != nil {
+		finish(-1, err)
+		return nil, err
+	}
+
+	// Finish the hooks of the wrapped database first, if any
+	inner := rows.finish
+	rows.finish = func(n int64, err error) {
+		inner(n, err)
+		finish(n, err)
+	}
+	return rows, nil
+}
+
+func (h *hookedQueryable) structMapper() *mapper.Mapper {
+	return mapperOf(h.db)
+}
+
+// observedRows counts the rows read by a builder and finishes the query hooks once the rows are consumed or closed.
+// *sql.Rows can't be wrapped behind QueryContext, so only builders observe the rows through queryRows.
+type observedRows struct {
+	*sql.Rows
+	finish hook.Finish
+	count  int64
+}
+
+func (r *observedRows) Next() bool {
+	if r.Rows.Next() {
+		r.count++
+		return true
+	}
+
+	r.finish(r.count, r.Rows.Err())
+	return false
+}
+
+func (r *observedRows) Close() error {
+	err := r.Rows.Close()
+	r.finish(r.count, r.Rows.Err())
+	return err
+}
+
+// queryRows runs the query for a builder consuming the rows. Unlike QueryContext, the hooks of a database
+// wrapped by WithHooks observe the rows count and the reading time, and finish once the rows are consumed or closed.
+func queryRows(ctx context.Context, db Readable, query string, args ...any) (*observedRows, error) {
+	if observed, ok := db.(interface {
+		queryRows(context.Context, string, ...any) (*observedRows, error)
+	}); ok {
+		return observed.queryRows(ctx, query, args...)
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &observedRows{Rows: rows, finish: func(int64, error) {}}, nil
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
+
+	"github.com/mekramy/gosql/internal/mapper"
 )
 
 // NewInserter initializes and returns a new Inserter instance for the given Executable interface.
 func NewInserter[T any](e Executable) Inserter[T] {
 	return &inserter[T]{
-		db: e,
+		db:       e,
+		mapper:   mapperOf(e),
+		conflict: make([]string, 0),
 		option: &options{
 			only:    make([]string, 0),
 			exclude: make([]string, 0),
@@ -23,14 +28,39 @@ type Inserter[T any] interface {
 	// Table sets the target table for the insert operation.
 	Table(table string) Inserter[T]
 
+	// OnConflict sets the unique key columns used by Upsert.
+	// MySQL resolves the conflicting key itself, so these columns are only excluded from the update set.
+	OnConflict(columns ...string) Inserter[T]
+
+	// DoNothing makes Upsert ignore conflicting rows instead of updating them.
+	DoNothing() Inserter[T]
+
+	// Returning copies LastInsertId() of Insert and Upsert into the integer field of dest
+	// tagged with the `autoincrement` option (e.g. `db:"id,autoincrement"`).
+	Returning(dest *T) Inserter[T]
+
 	// Insert performs an insert operation with the given record and options.
 	Insert(ctx context.Context, record T, options ...RepositoryOption) (sql.Result, error)
+
+	// InsertMany inserts the given records using multi-row INSERT statements.
+	// Records are chunked to stay below the MySQL parameter limit, run it inside a transaction for atomicity.
+	// Returns the total number of inserted rows.
+	InsertMany(ctx context.Context, records []T, options ...RepositoryOption) (int64, error)
+
+	// Upsert performs an insert operation and updates the existing row on duplicate key.
+	// Updated columns are derived from the record fields, excluding the conflict and pk columns.
+	// The inserted row is referenced with a row alias, which requires MySQL 8.0.19 or later (not MariaDB).
+	Upsert(ctx context.Context, record T, options ...RepositoryOption) (sql.Result, error)
 }
 
 type inserter[T any] struct {
-	db     Executable
-	table  string
-	option *options
+	db       Executable
+	mapper   *mapper.Mapper
+	table    string
+	conflict []string
+	nothing  bool
+	dest     *T
+	option   *options
 }
 
 func (i *inserter[T]) Table(t string) Inserter[T] {
@@ -38,6 +68,21 @@ func (i *inserter[T]) Table(t string) Inserter[T] {
 	return i
 }
 
+func (i *inserter[T]) OnConflict(columns ...string) Inserter[T] {
+	i.conflict = append(i.conflict, columns...)
+	return i
+}
+
+func (i *inserter[T]) DoNothing() Inserter[T] {
+	i.nothing = true
+	return i
+}
+
+func (i *inserter[T]) Returning(dest *T) Inserter[T] {
+	i.dest = dest
+	return i
+}
+
 func (i *inserter[T]) Insert(ctx context.Context, v T, options ...RepositoryOption) (sql.Result, error) {
 	if !isStruct[T]() {
 		return nil, ErrStructOnly
@@ -51,19 +96,145 @@ func (i *inserter[T]) Insert(ctx context.Context, v T, options ...RepositoryOpti
 		opt(i.option)
 	}
 
-	columns := structColumns(v, i.option.only, i.option.exclude)
-	values := structValues(v, i.option.only, i.option.exclude)
-	placeholders := make([]string, 0, len(columns))
+	columns, values := structFields(i.mapper, v, mapper.Insert, i.option.only, i.option.exclude)
+
+	cmd := fmt.Sprintf("%s;", i.insertSQL(columns))
+	return i.exec(ctx, cmd, values...)
+}
+
+func (i *inserter[T]) InsertMany(ctx context.Context, records []T, options ...RepositoryOption) (int64, error) {
+	if !isStruct[T]() {
+		return 0, ErrStructOnly
+	}
+
+	if i.table == "" {
+		return 0, ErrEmptySQL
+	}
+
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	for _, opt := range options {
+		opt(i.option)
+	}
+
+	columns, _ := structFields(i.mapper, records[0], mapper.Bulk, i.option.only, i.option.exclude)
+	if len(columns) == 0 {
+		return 0, ErrEmptySQL
+	}
+
+	var affected int64
+	size := batchSize(len(columns), i.option.batch)
+	for start := 0; start < len(records); start += size {
+		end := min(start+size, len(records))
+		rows := make([]string, 0, end-start)
+		values := make([]any, 0, (end-start)*len(columns))
+
+		for _, record := range records[start:end] {
+			placeholders := make([]string, 0, len(columns))
+			_, fields := structFields(i.mapper, record, mapper.Bulk, i.option.only, i.option.exclude)
+			for _, value := range fields {
+				if _, ok := value.(mapper.Default); ok {
+					placeholders = append(placeholders, "DEFAULT")
+				} else {
+					values = append(values, value)
+					placeholders = append(placeholders, "?")
+				}
+			}
+			rows = append(rows, "("+strings.Join(placeholders, ",")+")")
+		}
+
+		cmd := fmt.Sprintf(
+			"INSERT INTO %s (%s) VALUES %s;",
+			quoteTable(i.table),
+			strings.Join(columns, ","),
+			strings.Join(rows, ","),
+		)
+
+		res, err := i.db.ExecContext(ctx, cmd, values...)
+		if err != nil {
+			return affected, classify(err)
+		}
 
+		count, err := res.RowsAffected()
+		if err != nil {
+			return affected, err
+		}
+		affected += count
+	}
+
+	return affected, nil
+}
+
+func (i *inserter[T]) Upsert(ctx context.Context, v T, options ...RepositoryOption) (sql.Result, error) {
+	if !isStruct[T]() {
+		return nil, ErrStructOnly
+	}
+
+	if i.table == "" {
+		return nil, ErrEmptySQL
+	}
+
+	for _, opt := range options {
+		opt(i.option)
+	}
+
+	columns, values := structFields(i.mapper, v, mapper.Insert, i.option.only, i.option.exclude)
+	if len(columns) == 0 {
+		return nil, ErrEmptySQL
+	}
+
+	// Resolve update set, skipping conflict and primary key columns
+	keys := slices.Concat(primaryKeys[T](i.mapper), i.conflict)
+	updates := make([]string, 0, len(columns))
+	for _, col := range columns {
+		if !slices.Contains(keys, strings.Trim(col, "`")) {
+			updates = append(updates, fmt.Sprintf("%s = new.%s", col, col))
+		}
+	}
+
+	// MySQL has no DO NOTHING clause, assign the first column to itself instead
+	if i.nothing || len(updates) == 0 {
+		updates = []string{fmt.Sprintf("%s = %s", columns[0], columns[0])}
+	}
+
+	// Refer to the inserted row through an alias, VALUES() is deprecated since MySQL 8.0.20
+	cmd := fmt.Sprintf(
+		"%s AS new ON DUPLICATE KEY UPDATE %s;",
+		i.insertSQL(columns),
+		strings.Join(updates, ","),
+	)
+	return i.exec(ctx, cmd, values...)
+}
+
+// exec executes the command and copies the last insert id into dest if requested.
+func (i *inserter[T]) exec(ctx context.Context, cmd string, args ...any) (sql.Result, error) {
+	res, err := i.db.ExecContext(ctx, cmd, args...)
+	if err != nil || i.dest == nil {
+		return res, classify(err)
+	}
+
+	// Zero id means no auto increment value was generated (e.g. ignored duplicate)
+	id, err := res.LastInsertId()
+	if err != nil || id == 0 {
+		return res, err
+	}
+
+	return res, setAutoIncrement(i.mapper, i.dest, id)
+}
+
+// insertSQL generates the INSERT INTO statement for the given columns without a trailing semicolon.
+func (i *inserter[T]) insertSQL(columns []string) string {
+	placeholders := make([]string, 0, len(columns))
 	for range columns {
 		placeholders = append(placeholders, "?")
 	}
 
-	cmd := fmt.Sprintf(
-		"INSERT INTO `%s` (%s) VALUES (%s);",
-		i.table,
+	return fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		quoteTable(i.table),
 		strings.Join(columns, ","),
 		strings.Join(placeholders, ","),
 	)
-	return i.db.ExecContext(ctx, cmd, values...)
 }
@@ -2,22 +2,91 @@ package mysql
 
 import (
 	"context"
-	"sync"
+	"fmt"
+	"time"
+
+	"github.com/mekramy/gosql/internal/pool"
 )
 
+// ConfigResolver returns the config of the named connection, e.g. to place tenants on different hosts.
+// The connection name is used as the database name if the returned config has no database.
+// A nil config fails the connection with ErrNilConfig.
+type ConfigResolver func(name string) (Config, error)
+
+// HealthFunc receives the health changes of the named connection, err is nil when the connection recovers.
+type HealthFunc func(name string, err error)
+
+// ManagerOption configures a ConnectionManager.
+type ManagerOption func(*managerOptions)
+
+// MaxPools returns a ManagerOption function that limits the number of open connections.
+// The least recently used connection is closed when the limit is exceeded. Zero means no limit.
+func MaxPools(n int) ManagerOption {
+	return func(o *managerOptions) {
+		o.max = n
+	}
+}
+
+// IdleTimeout returns a ManagerOption function that closes connections unused for the given duration.
+// Zero disables idle eviction.
+func IdleTimeout(d time.Duration) ManagerOption {
+	return func(o *managerOptions) {
+		o.idle = d
+	}
+}
+
+// ResolveConfig returns a ManagerOption function that resolves the config of each connection,
+// instead of using the manager config for all connections.
+func ResolveConfig(resolver ConfigResolver) ManagerOption {
+	return func(o *managerOptions) {
+		o.resolver = resolver
+	}
+}
+
+// MonitorHealth returns a ManagerOption function that pings the open connections every interval
+// in the background, and calls fn when a connection becomes unhealthy or recovers.
+func MonitorHealth(interval time.Duration, fn HealthFunc) ManagerOption {
+	return func(o *managerOptions) {
+		o.interval = interval
+		o.health = fn
+	}
+}
+
+type managerOptions struct {
+	max      int
+	idle     time.Duration
+	resolver ConfigResolver
+	interval time.Duration
+	health   HealthFunc
+}
+
 // NewConnectionManager creates and returns a new ConnectionManager instance.
-func NewConnectionManager(config Config) ConnectionManager {
-	return &manager{
-		config:      config,
-		connections: make(map[string]Connection),
+func NewConnectionManager(config Config, options ...ManagerOption) ConnectionManager {
+	option := &managerOptions{}
+	for _, opt := range options {
+		opt(option)
 	}
+
+	m := &manager{config: config, resolver: option.resolver}
+	m.connections = pool.New(m.open, option.max, option.idle)
+	m.connections.Watch()
+	if option.health != nil {
+		m.connections.Monitor(
+			option.interval,
+			func(ctx context.Context, c Connection) error { return c.Ping(ctx) },
+			option.health,
+		)
+	}
+	return m
 }
 
 // ConnectionManager handles multiple MySQL database connections.
+// Evicted connections are closed, so callers should resolve connections per unit of work instead of keeping them.
 type ConnectionManager interface {
 	// Add registers a new database connection with the given name.
 	// Replaces the connection if it already exists.
 	// Returns an error if closing the existing connection fails.
+	// The connection is closed and ErrManagerClosed returned if the manager is closed.
 	Add(name string, db Connection) error
 
 	// Connect establishes a new database connection and stores it.
@@ -26,6 +95,8 @@ type ConnectionManager interface {
 	Connect(ctx context.Context, name string) error
 
 	// Resolve retrieves an existing connection or creates a new one if not found.
+	// Concurrent calls for the same name share a single connection attempt.
+	// Returns ErrManagerClosed if the manager is closed, even while the connection is being opened.
 	Resolve(ctx context.Context, name string) (Connection, error)
 
 	// Get retrieves a database connection by name.
@@ -36,6 +107,9 @@ type ConnectionManager interface {
 	// Returns an error if closing the connection fails.
 	Remove(name string) error
 
+	// Stats returns the sum of the open connections pool statistics.
+	Stats() Stats
+
 	// Close shuts down all active database connections.
 	// Returns an error if closing any connection fails.
 	Close() error
@@ -43,104 +117,60 @@ type ConnectionManager interface {
 
 type manager struct {
 	config      Config
-	connections map[string]Connection
-	mutex       sync.RWMutex
+	resolver    ConfigResolver
+	connections *pool.Pool[Connection]
 }
 
-func (m *manager) remove(n string) error {
-	if db, exists := m.connections[n]; exists {
-		if err := db.Close(); err != nil {
-			return err
-		}
-		delete(m.connections, n)
+// open establishes the connection of the given name.
+func (m *manager) open(ctx context.Context, n string) (Connection, error) {
+	if m.resolver == nil {
+		return New(ctx, m.config.buildFor(n))
 	}
-	return nil
-}
-
-func (m *manager) Add(n string, db Connection) error {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
 
-	// Close existing connection if present
-	if err := m.remove(n); err != nil {
-		return err
+	resolved, err := m.resolver(n)
+	if err != nil {
+		return nil, err
 	}
 
-	// Add new connection
-	m.connections[n] = db
-	return nil
-}
-
-func (m *manager) Connect(ctx context.Context, n string) error {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-
-	// Close existing connection if present
-	if err := m.remove(n); err != nil {
-		return err
+	c, ok := resolved.(*config)
+	if resolved == nil || (ok && c == nil) {
+		return nil, fmt.Errorf("%w: %s", ErrNilConfig, n)
 	}
 
-	// Create new connection
-	db, err := New(ctx, m.config.buildFor(n))
-	if err != nil {
-		return err
+	if ok && c.database != "" {
+		return New(ctx, c.Build())
 	}
-
-	m.connections[n] = db
-	return nil
+	return New(ctx, resolved.buildFor(n))
 }
 
-func (m *manager) Resolve(ctx context.Context, n string) (Connection, error) {
-	// Return existing connection if found
-	m.mutex.RLock()
-	db, exists := m.connections[n]
-	m.mutex.RUnlock()
-	if exists {
-		return db, nil
-	}
-
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-
-	// Recheck in case the connection was created in the meantime
-	if db, exists = m.connections[n]; exists {
-		return db, nil
-	}
+func (m *manager) Add(n string, db Connection) error {
+	return m.connections.Add(n, db)
+}
 
-	// Establish new connection
-	db, err := New(ctx, m.config.buildFor(n))
-	if err != nil {
-		return nil, err
-	}
+func (m *manager) Connect(ctx context.Context, n string) error {
+	return m.connections.Connect(ctx, n)
+}
 
-	m.connections[n] = db
-	return db, nil
+func (m *manager) Resolve(ctx context.Context, n string) (Connection, error) {
+	return m.connections.Resolve(ctx, n)
 }
 
 func (m *manager) Get(n string) (Connection, bool) {
-	m.mutex.RLock()
-	db, exists := m.connections[n]
-	m.mutex.RUnlock()
-	return db, exists
+	return m.connections.Get(n)
 }
 
 func (m *manager) Remove(n string) error {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-	return m.remove(n)
+	return m.connections.Remove(n)
+}
+
+func (m *manager) Stats() Stats {
+	var stats Stats
+	m.connections.Range(func(_ string, c Connection) {
+		stats = stats.Add(c.Stats())
+	})
+	return stats
 }
 
 func (m *manager) Close() error {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-
-	var finalErr error
-	for name, conn := range m.connections {
-		if err := conn.Close(); err != nil {
-			finalErr = err
-			continue
-		}
-		delete(m.connections, name)
-	}
-	return finalErr
+	return m.connections.Close()
 }
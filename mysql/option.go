@@ -16,7 +16,16 @@ func SkipFields(fields ...string) RepositoryOption {
 	}
 }
 
+// BatchSize returns a RepositoryOption function that limits the number of records per statement in bulk operations.
+// Batches are always capped to stay below the MySQL parameter limit.
+func BatchSize(size int) RepositoryOption {
+	return func(o *options) {
+		o.batch = size
+	}
+}
+
 type options struct {
 	only    []string
 	exclude []string
+	batch   int
 }
@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mekramy/gosql/internal/mapper"
+)
+
+// Relation describes the related table of a struct field loaded by Finder.Preload.
+// Relations can also be described by the `rel` struct tag
+// (e.g. `rel:"has_many,table=roles,fk=user_id"` or `rel:"belongs_to,table=teams,fk=team_id,ref=id"`).
+type Relation = mapper.Relation
+
+// RelationKind specifies how the records of a relation are linked.
+type RelationKind = mapper.RelationKind
+
+// Supported relation kinds.
+const (
+	HasMany   = mapper.HasMany
+	HasOne    = mapper.HasOne
+	BelongsTo = mapper.BelongsTo
+)
+
+// preload describes a relation field requested by Finder.Preload.
+type preload struct {
+	field    string
+	relation *Relation
+}
+
+// loadRelated loads the records of a related table whose column matches one of the keys.
+// Keys are chunked to stay below the MySQL parameter limit.
+func loadRelated(ctx context.Context, db Readable, m *mapper.Mapper, typ reflect.Type, table, column string, keys []any) ([]reflect.Value, error) {
+	meta := m.Of(typ)
+	selection := "*"
+	if len(meta.Columns) > 0 {
+		selection = strings.Join(meta.Columns, ",")
+	}
+
+	results := make([]reflect.Value, 0, len(keys))
+	size := batchSize(1, 0)
+	for start := 0; start < len(keys); start += size {
+		chunk := keys[start:min(start+size, len(keys))]
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(chunk)), ",")
+
+		cmd := fmt.Sprintf(
+			"SELECT %s FROM %s WHERE %s IN (%s);",
+			selection,
+			quoteTable(table),
+			quoteField(column),
+			placeholders,
+		)
+
+		rows, err := queryRows(ctx, db, cmd, chunk...)
+		if err != nil {
+			return nil, classify(err)
+		}
+
+		columns, err := rows.Columns()
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		for rows.Next() {
+			result := reflect.New(typ)
+			pointers, err := meta.Pointers(result.Elem(), columns)
+			if err == nil {
+				err = rows.Scan(pointers...)
+			}
+
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			results = append(results, result)
+		}
+
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, classify(err)
+		}
+	}
+	return results, nil
+}
@@ -3,6 +3,9 @@ package mysql_test
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/mekramy/gosql/mysql"
@@ -71,6 +74,20 @@ func TestRepository(t *testing.T) {
 		}
 	})
 
+	t.Run("Errors", func(t *testing.T) {
+		_, err := mysql.NewInserter[User](conn.Database()).
+			Table("users").
+			Insert(ctx, User{Id: 1, Name: "Duplicate"})
+		if !errors.Is(err, mysql.ErrUniqueViolation) {
+			t.Fatalf("expected unique violation, got %v", err)
+		}
+
+		var sqlErr *mysql.SQLError
+		if !errors.As(err, &sqlErr) || sqlErr.Constraint != "PRIMARY" {
+			t.Fatalf("expected PRIMARY constraint, got %v", sqlErr)
+		}
+	})
+
 	t.Run("Update", func(t *testing.T) {
 		err = conn.Transaction(ctx, func(tx *sql.Tx) error {
 			for idx, name := range []string{"John Doe New", "Jack Ma New"} {
@@ -95,6 +112,52 @@ func TestRepository(t *testing.T) {
 		}
 	})
 
+	t.Run("Upsert", func(t *testing.T) {
+		expect := func(name string) {
+			t.Helper()
+			user, err := mysql.NewFinder[User](conn.Database()).
+				Query("SELECT * FROM users WHERE id = ?;").
+				Struct(ctx, 2)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if user == nil || user.Name != name {
+				t.Fatalf("expected %q, got %v", name, user)
+			}
+		}
+
+		// Existing row is updated
+		_, err := mysql.NewInserter[User](conn.Database()).
+			Table("users").
+			OnConflict("id").
+			Upsert(ctx, User{Id: 2, Name: "Jack Ma Upserted"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		expect("Jack Ma Upserted")
+
+		// Conflicting row is ignored
+		_, err = mysql.NewInserter[User](conn.Database()).
+			Table("users").
+			OnConflict("id").
+			DoNothing().
+			Upsert(ctx, User{Id: 2, Name: "Ignored"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		expect("Jack Ma Upserted")
+
+		_, err = mysql.NewInserter[User](conn.Database()).
+			Table("users").
+			OnConflict("id").
+			Upsert(ctx, User{Id: 2, Name: "Jack Ma New"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		expect("Jack Ma New")
+	})
+
 	t.Run("Count", func(t *testing.T) {
 		count, err := mysql.NewCounter(conn.Database()).
 			Query("SELECT COUNT(*) FROM users;").
@@ -108,6 +171,40 @@ func TestRepository(t *testing.T) {
 		}
 	})
 
+	t.Run("Aggregate", func(t *testing.T) {
+		users := mysql.NewCounter(conn.Database()).Query("SELECT * FROM users WHERE id > ?;")
+
+		exists, err := users.Exists(ctx, 0)
+		if err != nil || !exists {
+			t.Fatalf("expected users to exist, got %v", err)
+		}
+
+		exists, err = users.Exists(ctx, 100)
+		if err != nil || exists {
+			t.Fatalf("expected no users, got %v, %v", exists, err)
+		}
+
+		names, err := mysql.CountBy[string](ctx, users, "name", 0)
+		if err != nil || len(names) != 2 {
+			t.Fatalf("expected 2 names, got %v, %v", names, err)
+		}
+
+		sum, err := mysql.Sum[int64](ctx, users, "id", 0)
+		if err != nil || sum == nil || *sum != 3 {
+			t.Fatalf("expected sum 3, got %v, %v", sum, err)
+		}
+
+		max, err := mysql.Max[int64](ctx, users, "id", 100)
+		if err != nil || max != nil {
+			t.Fatalf("expected nil max for empty set, got %v, %v", max, err)
+		}
+
+		type wrapped struct{ mysql.Counter }
+		if _, err := mysql.Sum[int64](ctx, wrapped{users}, "id", 0); !errors.Is(err, mysql.ErrUnsupportedCounter) {
+			t.Fatalf("expected ErrUnsupportedCounter, got %v", err)
+		}
+	})
+
 	t.Run("Single", func(t *testing.T) {
 		jack, err := mysql.NewFinder[User](conn.Database()).
 			Query("SELECT * FROM users WHERE id = ?;").
@@ -126,6 +223,27 @@ func TestRepository(t *testing.T) {
 		}
 	})
 
+	t.Run("NotFound", func(t *testing.T) {
+		finder := mysql.NewFinder[User](conn.Database()).
+			Query("SELECT * FROM users WHERE id = ?;")
+
+		user, err := finder.Struct(ctx, 100)
+		if err != nil || user != nil {
+			t.Fatalf("expected nil user and no error, got %v, %v", user, err)
+		}
+
+		if _, err := finder.Strict().Struct(ctx, 100); !errors.Is(err, mysql.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+
+		_, err = mysql.NewFinder[User](conn.Database()).
+			Query("SELECT * FROM users;").
+			StructExactlyOne(ctx)
+		if !errors.Is(err, mysql.ErrTooManyRows) {
+			t.Fatalf("expected ErrTooManyRows, got %v", err)
+		}
+	})
+
 	t.Run("Multiple", func(t *testing.T) {
 		users, err := mysql.NewFinder[User](conn.Database()).
 			Query("SELECT * FROM users;").
@@ -140,4 +258,620 @@ func TestRepository(t *testing.T) {
 		}
 	})
 
+	t.Run("Pointers", func(t *testing.T) {
+		users, err := mysql.NewFinder[*User](conn.Database()).
+			Query("SELECT * FROM users;").
+			Structs(ctx)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(users) != 2 || users[0] == nil {
+			t.Fatalf("expected 2 user, got %d", len(users))
+		}
+	})
+
+	t.Run("Iter", func(t *testing.T) {
+		finder := mysql.NewFinder[User](conn.Database()).
+			Query("SELECT * FROM users ORDER BY id;").
+			WithTransformer(func(u *User) error {
+				u.Name = strings.ToUpper(u.Name)
+				return nil
+			})
+
+		names := make([]string, 0)
+		for user, err := range finder.Iter(ctx) {
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			names = append(names, user.Name)
+			break
+		}
+
+		count := 0
+		err := finder.Each(ctx, func(u User) error {
+			count++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(names) != 1 || names[0] != strings.ToUpper(names[0]) || count != 2 {
+			t.Fatalf("expected 1 transformed name and 2 rows, got %v and %d", names, count)
+		}
+	})
+
+	t.Run("Values", func(t *testing.T) {
+		ids, err := mysql.NewFinder[int64](conn.Database()).
+			Query("SELECT id FROM users ORDER BY id;").
+			Values(ctx)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(ids) != 2 || ids[0] != 1 {
+			t.Fatalf("expected [1 2] ids, got %v", ids)
+		}
+
+		rows, err := mysql.NewFinder[any](conn.Database()).
+			Query("SELECT id, name FROM users ORDER BY id;").
+			Maps(ctx)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(rows) != 2 || rows[0]["name"] == nil {
+			t.Fatalf("expected 2 rows, got %v", rows)
+		}
+	})
+
+	t.Run("Hooks", func(t *testing.T) {
+		perConn, perCall := &hookRecorder{}, &hookRecorder{}
+		db := mysql.WithHooks(conn.Database(), perConn)
+
+		count, err := mysql.NewCounter(db).
+			Query("SELECT COUNT(*) FROM users;").
+			Count(mysql.ContextWithHooks(ctx, perCall))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		users, err := mysql.NewFinder[User](db).
+			Query("SELECT * FROM users;").
+			Structs(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(perCall.events) != 1 || len(perConn.events) != 2 {
+			t.Fatalf("expected 1 per call and 2 per connection events, got %d and %d", len(perCall.events), len(perConn.events))
+		}
+
+		event := perConn.events[1]
+		if event.Operation != mysql.OpQuery || event.SQL == "" || event.Err != nil || count != 2 || len(users) != 2 {
+			t.Fatalf("unexpected query event %+v", event)
+		}
+
+		executed := &hookRecorder{}
+		if _, err := mysql.NewCounter(conn.Executor()).
+			Query("SELECT COUNT(*) FROM users;").
+			Count(mysql.ContextWithHooks(ctx, executed)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(executed.events) != 1 {
+			t.Fatalf("expected 1 executor event, got %d", len(executed.events))
+		}
+
+		// Query hooks finish once the rows are read, through other wrappers too
+		named := mysql.WithNameMapper(db, mysql.SnakeCase)
+		for range mysql.NewFinder[User](named).Query("SELECT * FROM users;").Iter(ctx) {
+			break
+		}
+
+		if _, err := mysql.NewFinder[User](named).Query("SELECT * FROM users;").Structs(ctx); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(perConn.events) != 4 || perConn.events[1].Rows != 2 || perConn.events[2].Rows != 1 || perConn.events[3].Rows != 2 {
+			t.Fatalf("expected 2, 1 and 2 rows read, got %+v", perConn.events)
+		}
+	})
+
+	t.Run("Returning", func(t *testing.T) {
+		type AutoUser struct {
+			Id   int    `db:"id,autoincrement"`
+			Name string `db:"name"`
+		}
+
+		u := AutoUser{Name: "Elon Musk"}
+		_, err := mysql.NewInserter[AutoUser](conn.Database()).
+			Table("users").
+			Returning(&u).
+			Insert(ctx, u, mysql.SkipFields("id"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if u.Id != 3 {
+			t.Fatalf("expected id 3, got %d", u.Id)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		_, err := mysql.NewDeleter[User](conn.Database()).
+			Table("users").
+			Where("id = ?", 3).
+			Restore(ctx)
+		if !errors.Is(err, mysql.ErrNoSoftDelete) {
+			t.Fatalf("expected ErrNoSoftDelete, got %v", err)
+		}
+
+		res, err := mysql.NewDeleter[User](conn.Database()).
+			Table("users").
+			Where("id = ?", 3).
+			Delete(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if count, _ := res.RowsAffected(); count != 1 {
+			t.Fatalf("expected 1 deleted row, got %d", count)
+		}
+	})
+
+}
+
+func TestBulkInsert(t *testing.T) {
+	type Log struct {
+		Id      int    `db:"id"`
+		Message string `db:"message"`
+	}
+	ctx := context.Background()
+	config := mysql.NewConfig().
+		Host("localhost").
+		User("root").
+		Password("root").
+		Database("test")
+
+	conn, err := mysql.New(ctx, config.Build())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer conn.Close()
+
+	for _, cmd := range []string{
+		"DROP TABLE IF EXISTS logs;",
+		"CREATE TABLE logs (id SERIAL PRIMARY KEY, message TEXT);",
+	} {
+		if _, err := mysql.NewCmd(conn.Database()).Command(cmd).Exec(ctx); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	logs := make([]Log, 0, 100)
+	for i := range 100 {
+		logs = append(logs, Log{Message: fmt.Sprintf("message %d", i)})
+	}
+
+	count, err := mysql.NewInserter[Log](conn.Database()).
+		Table("logs").
+		InsertMany(ctx, logs, mysql.SkipFields("id"), mysql.BatchSize(30))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if count != 100 {
+		t.Fatalf("expected 100 rows, got %d", count)
+	}
+}
+
+func TestGenericRepository(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `db:"created_by"`
+	}
+	type Product struct {
+		Audit
+		Id    int    `db:"id,pk"`
+		Title string `db:"title"`
+	}
+	ctx := context.Background()
+	config := mysql.NewConfig().
+		Host("localhost").
+		User("root").
+		Password("root").
+		Database("test")
+
+	conn, err := mysql.New(ctx, config.Build())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer conn.Close()
+
+	for _, cmd := range []string{
+		"DROP TABLE IF EXISTS products;",
+		"CREATE TABLE products (id INT PRIMARY KEY, title TEXT, created_by TEXT);",
+	} {
+		if _, err := mysql.NewCmd(conn.Database()).Command(cmd).Exec(ctx); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	repo := mysql.NewRepository[Product](conn.Database(), "products")
+
+	t.Run("Create", func(t *testing.T) {
+		for idx, title := range []string{"Book", "Pen"} {
+			product := Product{Audit: Audit{CreatedBy: "admin"}, Id: idx + 1, Title: title}
+			if _, err := repo.Create(ctx, product); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		// Update replaces every written column, including the embedded ones.
+		product := Product{Audit: Audit{CreatedBy: "editor"}, Id: 2, Title: "Pencil"}
+		if _, err := repo.Update(ctx, product); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		pencil, err := repo.FindByID(ctx, 2)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if pencil == nil || pencil.Title != "Pencil" || pencil.CreatedBy != "editor" {
+			t.Fatalf(`expected "Pencil" created by "editor", got %v`, pencil)
+		}
+
+		// Partial updates keep the other columns.
+		if _, err := repo.Update(ctx, Product{Id: 2, Title: "Marker"}, mysql.OnlyFields("title")); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		marker, err := repo.FindByID(ctx, 2)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if marker == nil || marker.Title != "Marker" || marker.CreatedBy != "editor" {
+			t.Fatalf(`expected "Marker" created by "editor", got %v`, marker)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if _, err := repo.Delete(ctx, 1); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		exists, err := repo.Exists(ctx, 1)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if exists {
+			t.Fatal("expected product to be deleted")
+		}
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		products, err := repo.FindAll(ctx, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		count, err := repo.Count(ctx, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(products) != 1 || count != 1 {
+			t.Fatalf("expected 1 product, got %d (count %d)", len(products), count)
+		}
+	})
+
+	t.Run("NameMapper", func(t *testing.T) {
+		type Named struct {
+			Id        int `db:"id,pk"`
+			Title     string
+			CreatedBy string
+		}
+
+		named := mysql.NewRepository[Named](mysql.WithNameMapper(conn.Database(), mysql.SnakeCase), "products")
+		if _, err := named.Create(ctx, Named{Id: 3, Title: "Ink", CreatedBy: "guest"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		ink, err := named.FindByID(ctx, 3)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if ink == nil || ink.Title != "Ink" || ink.CreatedBy != "guest" {
+			t.Fatalf(`expected "Ink" created by "guest", got %v`, ink)
+		}
+
+		// The executor runs on the context transaction with the same name mapper
+		executor := mysql.NewRepository[Named](mysql.WithNameMapper(conn.Executor(), mysql.SnakeCase), "products")
+		rollback := errors.New("rollback")
+		err = conn.TransactionContext(ctx, func(ctx context.Context) error {
+			if _, err := executor.Create(ctx, Named{Id: 4, Title: "Eraser", CreatedBy: "guest"}); err != nil {
+				return err
+			}
+
+			eraser, err := executor.FindByID(ctx, 4)
+			if err != nil {
+				return err
+			}
+
+			if eraser == nil || eraser.Title != "Eraser" || eraser.CreatedBy != "guest" {
+				return fmt.Errorf(`expected "Eraser" created by "guest", got %v`, eraser)
+			}
+			return rollback
+		})
+		if !errors.Is(err, rollback) {
+			t.Fatalf("expected rollback, got %v", err)
+		}
+
+		eraser, err := executor.FindByID(ctx, 4)
+		if err != nil || eraser != nil {
+			t.Fatalf("expected rolled back product, got %v, %v", eraser, err)
+		}
+	})
+
+	t.Run("Preload", func(t *testing.T) {
+		type Tag struct {
+			Id        int    `db:"id,pk"`
+			ProductId int    `db:"product_id"`
+			Name      string `db:"name"`
+		}
+		type Tagged struct {
+			Id    int    `db:"id,pk"`
+			Title string `db:"title"`
+			Tags  []Tag  `rel:"has_many,table=tags,fk=product_id"`
+		}
+
+		for _, cmd := range []string{
+			"DROP TABLE IF EXISTS tags;",
+			"CREATE TABLE tags (id INT PRIMARY KEY, product_id INT, name TEXT);",
+			"INSERT INTO tags (id, product_id, name) VALUES (1, 2, 'office'), (2, 2, 'school'), (3, 3, 'office');",
+		} {
+			if _, err := mysql.NewCmd(conn.Database()).Command(cmd).Exec(ctx); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+
+		products, err := mysql.NewFinder[Tagged](conn.Database()).
+			Query("SELECT id, title FROM products ORDER BY id;").
+			Preload("Tags").
+			Structs(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(products) != 2 || len(products[0].Tags) != 2 || len(products[1].Tags) != 1 {
+			t.Fatalf("expected [2 1] tags, got %v", products)
+		}
+	})
+}
+
+func TestSoftDelete(t *testing.T) {
+	type Post struct {
+		Id        int          `db:"id,pk"`
+		Title     string       `db:"title"`
+		DeletedAt sql.NullTime `db:"deleted_at,softdelete"`
+	}
+	ctx := context.Background()
+	config := mysql.NewConfig().
+		Host("localhost").
+		User("root").
+		Password("root").
+		Database("test")
+
+	conn, err := mysql.New(ctx, config.Build())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer conn.Close()
+
+	for _, cmd := range []string{
+		"DROP TABLE IF EXISTS posts;",
+		"CREATE TABLE posts (id INT PRIMARY KEY, title TEXT, deleted_at TIMESTAMP NULL);",
+	} {
+		if _, err := mysql.NewCmd(conn.Database()).Command(cmd).Exec(ctx); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	repo := mysql.NewRepository[Post](conn.Database(), "posts")
+	for idx, title := range []string{"First", "Second"} {
+		if _, err := repo.Create(ctx, Post{Id: idx + 1, Title: title}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	deleter := func(id int) mysql.Deleter[Post] {
+		return mysql.NewDeleter[Post](conn.Database()).Table("posts").Where("id = ?", id)
+	}
+
+	t.Run("Delete", func(t *testing.T) {
+		res, err := deleter(1).Delete(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if affected := rowsAffected(t, res); affected != 1 {
+			t.Fatalf("expected 1 deleted row, got %d", affected)
+		}
+
+		// Already deleted records keep their deletion time
+		res, err = deleter(1).Delete(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if affected := rowsAffected(t, res); affected != 0 {
+			t.Fatalf("expected 0 deleted rows, got %d", affected)
+		}
+
+		post, err := mysql.NewFinder[Post](conn.Database()).
+			Query("SELECT * FROM posts WHERE id = ?;").
+			Struct(ctx, 1)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if post == nil || !post.DeletedAt.Valid {
+			t.Fatalf("expected soft deleted post, got %v", post)
+		}
+	})
+
+	t.Run("Scope", func(t *testing.T) {
+		post, err := repo.FindByID(ctx, 1)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if post != nil {
+			t.Fatalf("expected deleted post to be hidden, got %v", post)
+		}
+
+		exists, err := repo.Exists(ctx, 1)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if exists {
+			t.Fatal("expected deleted post to not exist")
+		}
+
+		posts, err := repo.FindAll(ctx, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		count, err := repo.Count(ctx, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(posts) != 1 || posts[0].Id != 2 || count != 1 {
+			t.Fatalf("expected post 2 only, got %v (count %d)", posts, count)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		// Replacing a deleted record must not restore it
+		res, err := repo.Update(ctx, Post{Id: 1, Title: "Replaced"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if affected := rowsAffected(t, res); affected != 0 {
+			t.Fatalf("expected 0 updated rows, got %d", affected)
+		}
+
+		post, err := mysql.NewFinder[Post](conn.Database()).
+			Query("SELECT * FROM posts WHERE id = ?;").
+			Struct(ctx, 1)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if post == nil || !post.DeletedAt.Valid || post.Title != "First" {
+			t.Fatalf("expected untouched deleted post, got %v", post)
+		}
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		res, err := deleter(1).Restore(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if affected := rowsAffected(t, res); affected != 1 {
+			t.Fatalf("expected 1 restored row, got %d", affected)
+		}
+
+		// Records that are not deleted are left untouched
+		res, err = deleter(2).Restore(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if affected := rowsAffected(t, res); affected != 0 {
+			t.Fatalf("expected 0 restored rows, got %d", affected)
+		}
+
+		post, err := repo.FindByID(ctx, 1)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if post == nil || post.DeletedAt.Valid {
+			t.Fatalf("expected restored post, got %v", post)
+		}
+	})
+
+	t.Run("ForceDelete", func(t *testing.T) {
+		res, err := deleter(1).ForceDelete(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if affected := rowsAffected(t, res); affected != 1 {
+			t.Fatalf("expected 1 deleted row, got %d", affected)
+		}
+
+		count, err := mysql.NewCounter(conn.Database()).
+			Query("SELECT COUNT(*) FROM posts;").
+			Count(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if count != 1 {
+			t.Fatalf("expected 1 post, got %d", count)
+		}
+	})
+
+	t.Run("ErrNoSoftDelete", func(t *testing.T) {
+		type Plain struct {
+			Id int `db:"id,pk"`
+		}
+
+		_, err := mysql.NewDeleter[Plain](conn.Database()).
+			Table("posts").
+			Where("id = ?", 2).
+			Restore(ctx)
+		if !errors.Is(err, mysql.ErrNoSoftDelete) {
+			t.Fatalf("expected ErrNoSoftDelete, got %v", err)
+		}
+	})
+}
+
+// rowsAffected returns the affected rows of a command result.
+func rowsAffected(t *testing.T, res sql.Result) int64 {
+	t.Helper()
+	affected, err := res.RowsAffected()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return affected
+}
+
+type hookRecorder struct {
+	events []mysql.QueryEvent
+}
+
+func (r *hookRecorder) Before(ctx context.Context, event *mysql.QueryEvent) context.Context {
+	return ctx
+}
+
+func (r *hookRecorder) After(ctx context.Context, event *mysql.QueryEvent) {
+	r.events = append(r.events, *event)
 }
@@ -0,0 +1,149 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/mekramy/gosql/internal/retry"
+	"github.com/mekramy/gosql/internal/txhook"
+	"github.com/mekramy/gosql/internal/txopt"
+)
+
+// RetryPolicy configures how TransactionWithRetry re-runs a transaction.
+// Zero fields fall back to the defaults (3 attempts, 10ms base delay, 1s max delay).
+type RetryPolicy = retry.Policy
+
+// TxOptions configures the isolation level and access mode of a transaction.
+type TxOptions = txopt.Options
+
+// IsolationLevel specifies the transaction isolation level.
+type IsolationLevel = txopt.IsolationLevel
+
+// Supported isolation levels.
+const (
+	LevelDefault         = txopt.Default
+	LevelReadUncommitted = txopt.ReadUncommitted
+	LevelReadCommitted   = txopt.ReadCommitted
+	LevelRepeatableRead  = txopt.RepeatableRead
+	LevelSerializable    = txopt.Serializable
+)
+
+// transactions tracks the hooks of the active transactions.
+var transactions txhook.Registry
+
+// txKey is the context key of the enclosing transaction.
+type txKey struct{}
+
+// savepoints generates unique savepoint names for nested transactions.
+var savepoints atomic.Uint64
+
+// WithTx returns a copy of ctx carrying the transaction.
+// Connection.Executor runs commands on tx with the returned context, and Connection.Transaction
+// calls with it run as nested transactions of tx using savepoints.
+func WithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction stored in ctx by WithTx, or nil.
+func TxFromContext(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(txKey{}).(*sql.Tx)
+	return tx
+}
+
+// savepoint runs f within a savepoint of tx.
+// Rolls back to the savepoint on error or panic, releases it otherwise.
+func savepoint(ctx context.Context, tx *sql.Tx, f func(*sql.Tx) error) error {
+	name := fmt.Sprintf("sp_%d", savepoints.Add(1))
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return classify(err)
+	}
+
+	// Nested units share the tx handle, so their hooks replace the parent hooks until they complete
+	parent := transactions.Lookup(tx)
+	hooks, release := transactions.Register(tx)
+	defer release()
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			hooks.RolledBack()
+			panic(p)
+		}
+	}()
+
+	if err := f(tx); err != nil {
+		tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+		hooks.RolledBack()
+		return classify(err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		hooks.RolledBack()
+		return classify(err)
+	}
+
+	hooks.Committed(parent)
+	return nil
+}
+
+// isRetryable checks if the error is a deadlock (1213) or a lock wait timeout (1205).
+func isRetryable(err error) bool {
+	var myErr *driver.MySQLError
+	return errors.As(err, &myErr) && (myErr.Number == 1213 || myErr.Number == 1205)
+}
+
+// OnCommit registers fn to run after the transaction commits.
+// Callbacks of nested transactions run after the outermost transaction commits.
+// Returns ErrNoTransaction if tx is not an active transaction of Connection.Transaction.
+func OnCommit(tx *sql.Tx, fn func()) error {
+	hooks := transactions.Lookup(tx)
+	if hooks == nil {
+		return ErrNoTransaction
+	}
+
+	hooks.OnCommit(fn)
+	return nil
+}
+
+// OnRollback registers fn to run after the transaction, or the nested transaction, rolls back.
+// Returns ErrNoTransaction if tx is not an active transaction of Connection.Transaction.
+func OnRollback(tx *sql.Tx, fn func()) error {
+	hooks := transactions.Lookup(tx)
+	if hooks == nil {
+		return ErrNoTransaction
+	}
+
+	hooks.OnRollback(fn)
+	return nil
+}
+
+// OnCommitContext registers fn to run after the transaction stored in ctx commits.
+func OnCommitContext(ctx context.Context, fn func()) error {
+	return OnCommit(TxFromContext(ctx), fn)
+}
+
+// OnRollbackContext registers fn to run after the transaction stored in ctx rolls back.
+func OnRollbackContext(ctx context.Context, fn func()) error {
+	return OnRollback(TxFromContext(ctx), fn)
+}
+
+// sqlTxOptions converts the transaction options to sql.TxOptions.
+// MySQL has no deferrable transactions, so Deferrable is ignored.
+func sqlTxOptions(opts TxOptions) *sql.TxOptions {
+	options := &sql.TxOptions{ReadOnly: opts.ReadOnly}
+	switch opts.Isolation {
+	case txopt.ReadUncommitted:
+		options.Isolation = sql.LevelReadUncommitted
+	case txopt.ReadCommitted:
+		options.Isolation = sql.LevelReadCommitted
+	case txopt.RepeatableRead:
+		options.Isolation = sql.LevelRepeatableRead
+	case txopt.Serializable:
+		options.Isolation = sql.LevelSerializable
+	}
+	return options
+}
@@ -4,12 +4,40 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+
+	"github.com/mekramy/gosql/internal/mapper"
+	"github.com/mekramy/gosql/internal/pool"
+	"github.com/mekramy/gosql/internal/sqlerr"
 )
 
 // Commonly used errors for database operations.
 var (
-	ErrEmptySQL   = errors.New("SQL command cannot be empty")
-	ErrStructOnly = errors.New("expected type must be a struct")
+	ErrEmptySQL           = errors.New("SQL command cannot be empty")
+	ErrUnsupportedCounter = errors.New("counter must be created by NewCounter")
+	ErrStructOnly         = errors.New("expected type must be a struct")
+	ErrNoAutoIncrement    = errors.New("expected an integer field tagged with autoincrement")
+	ErrNoPrimaryKey       = errors.New("expected fields tagged with pk")
+	ErrPrimaryKeyMismatch = errors.New("expected a value for each primary key")
+	ErrUnknownColumn      = mapper.ErrUnknownColumn
+	ErrNotFound           = sqlerr.ErrNotFound
+	ErrTooManyRows        = sqlerr.ErrTooManyRows
+	ErrInvalidRelation    = mapper.ErrInvalidRelation
+	ErrNoSoftDelete       = errors.New("expected a field tagged with softdelete")
+	ErrNoTransaction      = errors.New("expected an active transaction")
+	ErrInvalidDSN         = errors.New("invalid DSN")
+	ErrNilConfig          = errors.New("config resolver returned a nil config")
+	ErrManagerClosed      = pool.ErrClosed
+)
+
+// Classified database errors returned by all executors, see SQLError.
+var (
+	ErrUniqueViolation     = sqlerr.ErrUniqueViolation
+	ErrForeignKeyViolation = sqlerr.ErrForeignKeyViolation
+	ErrNotNullViolation    = sqlerr.ErrNotNullViolation
+	ErrCheckViolation      = sqlerr.ErrCheckViolation
+	ErrDeadlock            = sqlerr.ErrDeadlock
+	ErrSerialization       = sqlerr.ErrSerialization
+	ErrLockTimeout         = sqlerr.ErrLockTimeout
 )
 
 // Transformer defines an interface for decoding and transforming data.
@@ -26,6 +54,15 @@ type Executable interface {
 	ExecContext(ctx context.Context, sql string, args ...any) (sql.Result, error)
 }
 
+// Condition defines an interface for dynamically built SQL conditions, such as query.ConditionBuilder.
+type Condition interface {
+	// SQL returns the conditions as a raw SQL string with '?' placeholders.
+	SQL() string
+
+	// Arguments returns the list of arguments associated with the conditions.
+	Arguments() []any
+}
+
 // Readable defines an interface for executing SQL queries.
 type Readable interface {
 	// QueryContext runs a SQL query with optional parameters and returns a pgx.Rows iterator.
@@ -36,3 +73,9 @@ type Readable interface {
 	// The provided context is used for managing timeouts and cancellations.
 	QueryRowContext(ctx context.Context, sql string, args ...any) *sql.Row
 }
+
+// Queryable defines an interface for both executing SQL commands and queries.
+type Queryable interface {
+	Executable
+	Readable
+}
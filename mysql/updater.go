@@ -5,12 +5,15 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+
+	"github.com/mekramy/gosql/internal/mapper"
 )
 
 // NewUpdater initializes and returns a new Updater instance for the given Executable interface.
 func NewUpdater[T any](e Executable) Updater[T] {
 	return &updater[T]{
-		db: e,
+		db:     e,
+		mapper: mapperOf(e),
 		option: &options{
 			only:    make([]string, 0),
 			exclude: make([]string, 0),
@@ -32,6 +35,7 @@ type Updater[T any] interface {
 
 type updater[T any] struct {
 	db     Executable
+	mapper *mapper.Mapper
 	table  string
 	where  string
 	args   []any
@@ -62,8 +66,11 @@ func (u *updater[T]) Update(ctx context.Context, v T, options ...RepositoryOptio
 		opt(u.option)
 	}
 
-	columns := structColumns(v, u.option.only, u.option.exclude)
-	values := structValues(v, u.option.only, u.option.exclude)
+	columns, values := structFields(u.mapper, v, mapper.Update, u.option.only, u.option.exclude)
+	if len(columns) == 0 {
+		return nil, ErrEmptySQL
+	}
+
 	values = append(values, u.args...)
 
 	for i, col := range columns {
@@ -71,10 +78,11 @@ func (u *updater[T]) Update(ctx context.Context, v T, options ...RepositoryOptio
 	}
 
 	cmd := fmt.Sprintf(
-		"UPDATE `%s` SET %s WHERE %s;",
-		u.table,
+		"UPDATE %s SET %s WHERE %s;",
+		quoteTable(u.table),
 		strings.Join(columns, ","),
 		u.where,
 	)
-	return u.db.ExecContext(ctx, cmd, values...)
+	res, err := u.db.ExecContext(ctx, cmd, values...)
+	return res, classify(err)
 }
@@ -3,10 +3,14 @@ package mysql
 import (
 	"fmt"
 	"reflect"
-	"slices"
 	"strings"
+
+	"github.com/mekramy/gosql/internal/mapper"
 )
 
+// maxParams is the maximum number of bind parameters allowed in a single MySQL statement.
+const maxParams = 65535
+
 // parseVariadic returns the first value from `vals` or the default value `def` if `vals` is empty.
 func parseVariadic[T any](def T, vals ...T) T {
 	if len(vals) > 0 {
@@ -15,11 +19,47 @@ func parseVariadic[T any](def T, vals ...T) T {
 	return def
 }
 
-// isStruct checks if the type of T is a struct.
+// batchSize returns the number of records per statement for bulk operations,
+// capped so that `columns` parameters per record stay below maxParams.
+func batchSize(columns, size int) int {
+	limit := max(maxParams/max(columns, 1), 1)
+	if size <= 0 || size > limit {
+		return limit
+	}
+	return size
+}
+
+// isStruct checks if the type of T is a struct or a pointer to a struct.
 func isStruct[T any](_ ...T) bool {
-	var v T
-	val := reflect.Indirect(reflect.ValueOf(v))
-	return val.Kind() == reflect.Struct
+	return structType[T]().Kind() == reflect.Struct
+}
+
+// structType returns the type of T, or the pointed type if T is a pointer.
+func structType[T any]() reflect.Type {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() == reflect.Pointer {
+		return typ.Elem()
+	}
+	return typ
+}
+
+// structValue dereferences v until it reaches a non pointer value.
+// Returns an invalid value if v is a nil pointer.
+func structValue(v any) reflect.Value {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Pointer {
+		val = val.Elem()
+	}
+	return val
+}
+
+// newRecord returns a new T, allocating the pointed struct if T is a pointer.
+func newRecord[T any]() T {
+	var result T
+	if typ := reflect.TypeFor[T](); typ.Kind() == reflect.Pointer {
+		result = reflect.New(typ.Elem()).Interface().(T)
+	}
+	return result
 }
 
 // compile replaces @placeholder in SQL query.
@@ -27,70 +67,92 @@ func compile(query string, replacements ...string) string {
 	return strings.NewReplacer(replacements...).Replace(query)
 }
 
-// structColumns extracts column names from the `db` struct tag, skipping unexported fields.
-func structColumns(v any, only, exclude []string) []string {
-	val := reflect.Indirect(reflect.ValueOf(v))
+// metadataOf returns the cached column mapping of T, or nil if T is not a struct.
+func metadataOf[T any](m *mapper.Mapper) *mapper.Metadata {
+	return m.Of(structType[T]())
+}
+
+// structFields extracts the quoted column names and values of a struct for the given mode.
+// In bulk mode, fields resolved to DEFAULT are returned as mapper.Default.
+func structFields(m *mapper.Mapper, v any, mode mapper.Mode, only, exclude []string) ([]string, []any) {
+	val := structValue(v)
 	if val.Kind() != reflect.Struct {
-		return nil
+		return nil, nil
 	}
+	return m.Of(val.Type()).Resolve(val, mode, only, exclude)
+}
 
-	typ := val.Type()
-	columns := make([]string, 0, typ.NumField())
+// structPointers returns pointers to the struct fields matching the given columns, in order.
+// Columns match the `db` tag or mapped name, or the untagged field name ignoring case and underscores.
+func structPointers(m *mapper.Mapper, v any, columns []string) ([]any, error) {
+	val := structValue(v)
+	if val.Kind() != reflect.Struct || !val.CanAddr() {
+		return nil, ErrStructOnly
+	}
+	return m.Of(val.Type()).Pointers(val, columns)
+}
 
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
+// setAutoIncrement assigns `id` to the integer field marked with the `autoincrement` tag option.
+func setAutoIncrement(m *mapper.Mapper, v any, id int64) error {
+	val := structValue(v)
+	if val.Kind() != reflect.Struct || !val.CanAddr() {
+		return ErrStructOnly
+	}
 
-		// Skip unexported fields.
-		if !field.IsExported() {
-			continue
-		}
+	field := m.Of(val.Type()).AutoIncrement
+	if field == nil {
+		return ErrNoAutoIncrement
+	}
 
-		// Extract valid 'db' tags (ignoring "-" and empty values).
-		if tag, ok := field.Tag.Lookup("db"); ok && !skipped(tag, only, exclude) {
-			columns = append(columns, quoteField(tag))
-		}
+	switch f := val.FieldByIndex(field.Index); f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.SetInt(id)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f.SetUint(uint64(id))
+	default:
+		return fmt.Errorf("%w: %s", ErrNoAutoIncrement, field.Name)
 	}
-	return columns
+	return nil
 }
 
-// structValues extracts field values from a struct based on the `db` tag, excluding unexported fields.
-func structValues(v any, only, exclude []string) []any {
-	val := reflect.Indirect(reflect.ValueOf(v))
-	if val.Kind() != reflect.Struct {
-		return nil
+// scanStruct scans the current row into a new T using the same field mapping as the write operations.
+func scanStruct[T any](m *mapper.Mapper, rows *observedRows, columns []string) (T, error) {
+	result := newRecord[T]()
+	pointers, err := structPointers(m, &result, columns)
+	if err != nil {
+		return result, err
 	}
 
-	typ := val.Type()
-	values := make([]any, 0, typ.NumField())
-
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-
-		// Skip unexported fields.
-		if !field.IsExported() {
-			continue
-		}
+	err = rows.Scan(pointers...)
+	return result, err
+}
 
-		// Add values for valid 'db' tags (not "-" or empty).
-		if tag, ok := field.Tag.Lookup("db"); ok && !skipped(tag, only, exclude) {
-			values = append(values, val.Field(i).Interface())
-		}
+// softDeleteColumn returns the column name of the T field tagged with the `softdelete` option, or an empty string.
+func softDeleteColumn[T any](m *mapper.Mapper) string {
+	if meta := metadataOf[T](m); meta != nil {
+		return meta.SoftDelete
 	}
-	return values
+	return ""
 }
 
-// skipped checks if a struct field's `db` tag should be skipped based on `only` and `exclude` lists.
-func skipped(tag string, only, exclude []string) bool {
-	if tag == "-" || tag == "" ||
-		(len(only) > 0 && !slices.Contains(only, tag)) ||
-		(len(exclude) > 0 && slices.Contains(exclude, tag)) {
-		return true
+// primaryKeys returns the column names of the T fields tagged with the `pk` option.
+func primaryKeys[T any](m *mapper.Mapper) []string {
+	if meta := metadataOf[T](m); meta != nil {
+		return meta.Keys
 	}
-
-	return false
+	return nil
 }
 
 // quoteField wraps a column name in backtick for SQL compatibility.
 func quoteField(field string) string {
 	return fmt.Sprintf("`%s`", field)
 }
+
+// quoteTable quotes each part of a table name, e.g. a schema qualified table name.
+func quoteTable(table string) string {
+	parts := strings.Split(table, ".")
+	for i, part := range parts {
+		parts[i] = quoteField(part)
+	}
+	return strings.Join(parts, ".")
+}
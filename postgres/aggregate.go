@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// CountBy executes the Counter query as a subquery and returns the number of rows
+// grouped by `column` (a column name or SQL expression of the query result).
+func CountBy[K comparable](ctx context.Context, c Counter, column string, args ...any) (map[K]int64, error) {
+	src, err := counterOf(c)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf("SELECT %s, COUNT(*) FROM (%s) AS aggregation GROUP BY %s;", column, src.source(), column)
+	rows, err := src.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, classify(err)
+	}
+	defer rows.Close()
+
+	result := make(map[K]int64)
+	for rows.Next() {
+		var key K
+		var count int64
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, classify(err)
+		}
+		result[key] = count
+	}
+	return result, classify(rows.Err())
+}
+
+// Sum executes the Counter query as a subquery and returns the sum of `column`.
+// Returns nil if the result set is empty or all values are NULL.
+func Sum[T any](ctx context.Context, c Counter, column string, args ...any) (*T, error) {
+	return aggregate[T](ctx, c, "SUM", column, args...)
+}
+
+// Avg executes the Counter query as a subquery and returns the average of `column`.
+// Returns nil if the result set is empty or all values are NULL.
+func Avg[T any](ctx context.Context, c Counter, column string, args ...any) (*T, error) {
+	return aggregate[T](ctx, c, "AVG", column, args...)
+}
+
+// Min executes the Counter query as a subquery and returns the minimum of `column` (e.g. numbers, time.Time).
+// Returns nil if the result set is empty or all values are NULL.
+func Min[T any](ctx context.Context, c Counter, column string, args ...any) (*T, error) {
+	return aggregate[T](ctx, c, "MIN", column, args...)
+}
+
+// Max executes the Counter query as a subquery and returns the maximum of `column` (e.g. numbers, time.Time).
+// Returns nil if the result set is empty or all values are NULL.
+func Max[T any](ctx context.Context, c Counter, column string, args ...any) (*T, error) {
+	return aggregate[T](ctx, c, "MAX", column, args...)
+}
+
+// aggregate executes the aggregate function over `column` of the Counter query and scans the nullable result.
+func aggregate[T any](ctx context.Context, c Counter, function, column string, args ...any) (*T, error) {
+	src, err := counterOf(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *T
+	sql := fmt.Sprintf("SELECT %s(%s) FROM (%s) AS aggregation;", function, column, src.source())
+	if err := src.db.QueryRow(ctx, sql, args...).Scan(&result); err != nil {
+		return nil, classify(err)
+	}
+	return result, nil
+}
+
+// counterOf returns the counter behind c, which must be created by NewCounter to be used as a subquery.
+func counterOf(c Counter) (*counter, error) {
+	src, ok := c.(*counter)
+	if !ok {
+		return nil, ErrUnsupportedCounter
+	}
+
+	if src.sql == "" {
+		return nil, ErrEmptySQL
+	}
+	return src, nil
+}
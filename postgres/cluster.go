@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mekramy/gosql/internal/cluster"
+)
+
+// Balancer specifies how a replica is selected for reads.
+type Balancer = cluster.Strategy
+
+const (
+	RoundRobin       = cluster.RoundRobin       // rotates over the healthy replicas
+	LeastConnections = cluster.LeastConnections // picks the healthy replica with the fewest acquired connections
+)
+
+// ClusterOption configures a Cluster.
+type ClusterOption func(*clusterOptions)
+
+// Balance returns a ClusterOption function that sets the replica selection strategy (RoundRobin by default).
+func Balance(balancer Balancer) ClusterOption {
+	return func(o *clusterOptions) {
+		o.balancer = balancer
+	}
+}
+
+// HealthCheck returns a ClusterOption function that sets the replicas health check interval (5s by default).
+// Replicas failing the check are ejected until a later check succeeds. Zero disables health checks.
+func HealthCheck(interval time.Duration) ClusterOption {
+	return func(o *clusterOptions) {
+		o.interval = interval
+	}
+}
+
+type clusterOptions struct {
+	balancer Balancer
+	interval time.Duration
+}
+
+type primaryKey struct{}
+
+// ForcePrimary returns a copy of ctx that routes the reads of a Cluster to the primary,
+// e.g. to read your own writes right after a write despite the replication lag.
+func ForcePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// NewCluster creates a Cluster of a primary and replica connections.
+// The cluster takes ownership of the connections and closes them on Close.
+func NewCluster(primary Connection, replicas []Connection, options ...ClusterOption) Cluster {
+	option := &clusterOptions{balancer: RoundRobin, interval: 5 * time.Second}
+	for _, opt := range options {
+		opt(option)
+	}
+
+	c := &pgxCluster{
+		primary: primary,
+		replicas: cluster.New(
+			replicas, option.balancer,
+			func(c Connection) int { return int(c.Database().Stat().AcquiredConns()) },
+			func(ctx context.Context, c Connection) error { return c.Ping(ctx) },
+		),
+		nodes: replicas,
+	}
+	c.replicas.Watch(option.interval)
+	return c
+}
+
+// Cluster represents a primary connection with read replicas.
+// As a Queryable, it runs Exec and CopyFrom on the primary, and Query and QueryRow on a healthy replica,
+// or on the primary when no replica is healthy or ctx is marked with ForcePrimary.
+// Builders mark the context of their RETURNING commands with ForcePrimary, since they are writes.
+// Commands always run on the transaction stored in the context, if any.
+// Transactions always run on the primary, and Stats sums the primary and replicas statistics.
+type Cluster interface {
+	Connection
+	Queryable
+	Copyable
+
+	// Primary returns the primary connection.
+	Primary() Connection
+}
+
+type pgxCluster struct {
+	primary  Connection
+	replicas *cluster.Replicas[Connection]
+	nodes    []Connection
+}
+
+// reader returns the transaction stored in ctx, or the pool to read from.
+func (c *pgxCluster) reader(ctx context.Context) Readable {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	if forced, _ := ctx.Value(primaryKey{}).(bool); !forced {
+		if replica, ok := c.replicas.Pick(); ok {
+			return replica.Database()
+		}
+	}
+	return c.primary.Database()
+}
+
+// writer returns the transaction stored in ctx, or the primary pool.
+func (c *pgxCluster) writer(ctx context.Context) interface {
+	Executable
+	Copyable
+} {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return c.primary.Database()
+}
+
+func (c *pgxCluster) Primary() Connection {
+	return c.primary
+}
+
+func (c *pgxCluster) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return c.writer(ctx).Exec(ctx, sql, args...)
+}
+
+func (c *pgxCluster) CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error) {
+	return c.writer(ctx).CopyFrom(ctx, table, columns, src)
+}
+
+func (c *pgxCluster) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return c.reader(ctx).Query(ctx, sql, args...)
+}
+
+func (c *pgxCluster) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return c.reader(ctx).QueryRow(ctx, sql, args...)
+}
+
+func (c *pgxCluster) Database() *pgxpool.Pool {
+	return c.primary.Database()
+}
+
+func (c *pgxCluster) Executor() Queryable {
+	return &hookedQueryable{db: c}
+}
+
+func (c *pgxCluster) Ping(ctx context.Context) error {
+	return c.primary.Ping(ctx)
+}
+
+func (c *pgxCluster) Stats() Stats {
+	stats := c.primary.Stats()
+	for _, replica := range c.nodes {
+		stats = stats.Add(replica.Stats())
+	}
+	return stats
+}
+
+func (c *pgxCluster) Transaction(ctx context.Context, f func(pgx.Tx) error, opts ...TxOptions) error {
+	return c.primary.Transaction(ctx, f, opts...)
+}
+
+func (c *pgxCluster) TransactionContext(ctx context.Context, f func(context.Context) error, opts ...TxOptions) error {
+	return c.primary.TransactionContext(ctx, f, opts...)
+}
+
+func (c *pgxCluster) TransactionWithRetry(ctx context.Context, f func(pgx.Tx) error, policy RetryPolicy, opts ...TxOptions) error {
+	return c.primary.TransactionWithRetry(ctx, f, policy, opts...)
+}
+
+func (c *pgxCluster) Close() error {
+	c.replicas.Close()
+
+	errs := []error{c.primary.Close()}
+	for _, replica := range c.nodes {
+		errs = append(errs, replica.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -51,5 +51,6 @@ func (c *commander) Exec(ctx context.Context, args ...any) (pgconn.CommandTag, e
 	}
 
 	sql := compile(c.sql, c.replacements...)
-	return c.db.Exec(ctx, sql, args...)
+	tag, err := c.db.Exec(ctx, sql, args...)
+	return tag, classify(err)
 }
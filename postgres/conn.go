@@ -5,8 +5,13 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mekramy/gosql/internal/pool"
+	"github.com/mekramy/gosql/internal/retry"
 )
 
+// Stats describes the connections of a pool.
+type Stats = pool.Stats
+
 // ConfigModifier modifies database config before creation.
 type ConfigModifier func(*pgxpool.Config)
 
@@ -39,12 +44,37 @@ type Connection interface {
 	// Database returns the underlying connection pool.
 	Database() *pgxpool.Pool
 
+	// Executor returns a Queryable that runs commands on the transaction stored in the context
+	// (see TransactionContext and WithTx), or on the pool otherwise.
+	// Repositories created with it stay transaction agnostic.
+	// It runs the per call hooks added with ContextWithHooks.
+	Executor() Queryable
+
 	// Ping verifies the database connection by sending a simple query.
 	Ping(ctx context.Context) error
 
+	// Stats returns the connection pool statistics.
+	Stats() Stats
+
 	// Transaction executes a function within a transaction.
-	// Commits if successful, rolls back on error.
-	Transaction(ctx context.Context, cb func(pgx.Tx) error, options ...pgx.TxOptions) error
+	// Commits if successful, rolls back on error or panic (the panic is propagated).
+	// If ctx carries an enclosing transaction (see WithTx), a savepoint is created instead
+	// and only the inner unit is rolled back on error, options are ignored in this case.
+	// The callback only receives the transaction: calling Transaction again with the outer ctx starts an
+	// independent transaction, use TransactionContext or WithTx(ctx, tx) to nest transactions.
+	// The transaction doesn't carry the name mapper of a WithNameMapper wrapper, wrap it again if needed.
+	// Options are the dialect neutral TxOptions rather than pgx.TxOptions, begin the transaction
+	// on Database() and store it with WithTx when pgx specific options (e.g. BeginQuery) are needed.
+	Transaction(ctx context.Context, cb func(pgx.Tx) error, options ...TxOptions) error
+
+	// TransactionContext executes a function within a transaction like Transaction,
+	// passing a context that carries the transaction for Executor and nested transactions.
+	TransactionContext(ctx context.Context, cb func(ctx context.Context) error, options ...TxOptions) error
+
+	// TransactionWithRetry executes a function within a transaction like Transaction, and re-runs the whole
+	// transaction with jittered backoff on serialization failures (40001) and deadlocks (40P01).
+	// Nested transactions are not retried, since the enclosing transaction must be retried as a whole.
+	TransactionWithRetry(ctx context.Context, cb func(pgx.Tx) error, policy RetryPolicy, options ...TxOptions) error
 
 	// Close terminates the database connection pool.
 	Close() error
@@ -58,22 +88,82 @@ func (d *pgxConnection) Database() *pgxpool.Pool {
 	return d.db
 }
 
+func (d *pgxConnection) Executor() Queryable {
+	return &hookedQueryable{db: &executor{db: d.db}}
+}
+
 func (d *pgxConnection) Ping(ctx context.Context) error {
 	return d.db.Ping(ctx)
 }
 
-func (d *pgxConnection) Transaction(ctx context.Context, f func(pgx.Tx) error, opts ...pgx.TxOptions) error {
-	tx, err := d.db.BeginTx(ctx, parseVariadic(pgx.TxOptions{}, opts...))
+func (d *pgxConnection) Stats() Stats {
+	stat := d.db.Stat()
+	return Stats{
+		Open:             int(stat.TotalConns()),
+		Idle:             int(stat.IdleConns()),
+		InUse:            int(stat.AcquiredConns()),
+		WaitCount:        stat.EmptyAcquireCount(),
+		WaitDuration:     stat.AcquireDuration(),
+		CanceledAcquires: stat.CanceledAcquireCount(),
+	}
+}
+
+func (d *pgxConnection) Transaction(ctx context.Context, f func(pgx.Tx) error, opts ...TxOptions) error {
+	parent := TxFromContext(ctx)
+
+	var tx pgx.Tx
+	var err error
+	if parent != nil {
+		tx, err = parent.Begin(ctx) // pseudo nested transaction using a savepoint
+	} else {
+		tx, err = d.db.BeginTx(ctx, pgxTxOptions(parseVariadic(TxOptions{}, opts...)))
+	}
+
 	if err != nil {
-		return err
+		return classify(err)
 	}
 
+	hooks, release := transactions.Register(tx)
+	defer release()
+
+	// Roll back and re-panic if the callback panics
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback(ctx)
+			hooks.RolledBack()
+			panic(p)
+		}
+	}()
+
 	if err := f(tx); err != nil {
 		tx.Rollback(ctx)
-		return err
+		hooks.RolledBack()
+		return classify(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		hooks.RolledBack()
+		return classify(err)
+	}
+
+	hooks.Committed(transactions.Lookup(parent))
+	return nil
+}
+
+func (d *pgxConnection) TransactionContext(ctx context.Context, f func(context.Context) error, opts ...TxOptions) error {
+	return d.Transaction(ctx, func(tx pgx.Tx) error {
+		return f(WithTx(ctx, tx))
+	}, opts...)
+}
+
+func (d *pgxConnection) TransactionWithRetry(ctx context.Context, f func(pgx.Tx) error, policy RetryPolicy, opts ...TxOptions) error {
+	if TxFromContext(ctx) != nil {
+		return d.Transaction(ctx, f, opts...)
 	}
 
-	return tx.Commit(ctx)
+	return retry.Do(ctx, policy, isRetryable, func() error {
+		return d.Transaction(ctx, f, opts...)
+	})
 }
 
 func (d *pgxConnection) Close() error {
@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -18,7 +20,9 @@ func NewCounter(r Readable) Counter {
 
 // Counter provides methods for constructing and executing SQL queries to count results.
 type Counter interface {
-	// Query sets the SQL query for counting rows.
+	// Query sets the SQL query for counting rows (e.g. "SELECT COUNT(*) FROM users WHERE ...").
+	// Exists and the CountBy, Sum, Avg, Min and Max functions expect a row query instead
+	// (e.g. "SELECT * FROM users WHERE ..."), which they run as a subquery.
 	Query(sql string) Counter
 
 	// Replace substitutes placeholders in the query string before execution.
@@ -29,6 +33,10 @@ type Counter interface {
 	// It uses the provided arguments for parameterized queries.
 	// Returns the count and any errors encountered.
 	Count(ctx context.Context, args ...any) (int64, error)
+
+	// Exists executes the row query as a subquery and reports whether it returns any row.
+	// A COUNT query always returns one row, so Exists is always true for it; use Count instead.
+	Exists(ctx context.Context, args ...any) (bool, error)
 }
 
 type counter struct {
@@ -56,8 +64,26 @@ func (c *counter) Count(ctx context.Context, args ...any) (int64, error) {
 	sql := compile(c.sql, c.replacements...)
 	err := c.db.QueryRow(ctx, sql, args...).Scan(&count)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		return 0, err
+		return 0, classify(err)
 	}
 
 	return count, nil
 }
+
+func (c *counter) Exists(ctx context.Context, args ...any) (bool, error) {
+	if c.sql == "" {
+		return false, ErrEmptySQL
+	}
+
+	var exists bool
+	sql := fmt.Sprintf("SELECT EXISTS (%s);", c.source())
+	if err := c.db.QueryRow(ctx, sql, args...).Scan(&exists); err != nil {
+		return false, classify(err)
+	}
+	return exists, nil
+}
+
+// source returns the compiled query without the trailing semicolon, to be used as a subquery.
+func (c *counter) source() string {
+	return strings.TrimSuffix(strings.TrimSpace(compile(c.sql, c.replacements...)), ";")
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mekramy/gosql/internal/sqlerr"
+)
+
+// SQLError is a classified database error, matching one of the classified errors
+// (e.g. ErrUniqueViolation) and the underlying *pgconn.PgError.
+type SQLError = sqlerr.Error
+
+// errorKinds maps the PostgreSQL error codes to the classified errors.
+var errorKinds = map[string]error{
+	"23505": sqlerr.ErrUniqueViolation,
+	"23503": sqlerr.ErrForeignKeyViolation,
+	"23502": sqlerr.ErrNotNullViolation,
+	"23514": sqlerr.ErrCheckViolation,
+	"40P01": sqlerr.ErrDeadlock,
+	"40001": sqlerr.ErrSerialization,
+	"55P03": sqlerr.ErrLockTimeout,
+}
+
+// classify wraps the PostgreSQL errors in a SQLError.
+func classify(err error) error {
+	return sqlerr.Classify(err, func(err error) *SQLError {
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) {
+			return nil
+		}
+
+		kind, ok := errorKinds[pgErr.Code]
+		if !ok {
+			return nil
+		}
+
+		return &SQLError{
+			Kind:       kind,
+			Code:       pgErr.Code,
+			Table:      pgErr.TableName,
+			Column:     pgErr.ColumnName,
+			Constraint: pgErr.ConstraintName,
+			Err:        err,
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// executor runs commands on the transaction stored in the context, or on the pool otherwise.
+type executor struct {
+	db *pgxpool.Pool
+}
+
+// target returns the transaction stored in ctx, or the pool.
+func (e *executor) target(ctx context.Context) interface {
+	Queryable
+	Copyable
+} {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return e.db
+}
+
+func (e *executor) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return e.target(ctx).Exec(ctx, sql, args...)
+}
+
+func (e *executor) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return e.target(ctx).Query(ctx, sql, args...)
+}
+
+func (e *executor) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return e.target(ctx).QueryRow(ctx, sql, args...)
+}
+
+func (e *executor) CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error) {
+	return e.target(ctx).CopyFrom(ctx, table, columns, src)
+}
@@ -3,14 +3,18 @@ package postgres
 import (
 	"context"
 	"errors"
+	"iter"
+	"reflect"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/mekramy/gosql/internal/mapper"
 )
 
 // NewFinder creates a new Finder instance with the provided Readable interface.
 func NewFinder[T any](r Readable) Finder[T] {
 	return &finder[T]{
 		db:           r,
+		mapper:       mapperOf(r),
 		sql:          "",
 		replacements: make([]string, 0),
 		transformers: make([]func(*T) error, 0),
@@ -32,18 +36,52 @@ type Finder[T any] interface {
 	// Rows executes the query and returns a pgx.Rows iterator for processing result rows.
 	Rows(ctx context.Context, args ...any) (pgx.Rows, error)
 
-	// Struct executes the query and retrieves a single result, or an error if the query fails.
+	// Preload loads the relation of the struct field named `field` into the results of
+	// Struct, StructExactlyOne and Structs, using one follow-up query per relation.
+	// The relation is described by the optional relation argument, or the `rel` tag of the field.
+	Preload(field string, relation ...Relation) Finder[T]
+
+	// Strict makes Struct and Value return ErrNotFound instead of nil, nil when the query returns no rows.
+	Strict() Finder[T]
+
+	// Struct executes the query and retrieves the first result, or an error if the query fails.
+	// Returns nil, nil if there are no rows, or ErrNotFound in strict mode.
 	Struct(ctx context.Context, args ...any) (*T, error)
 
+	// StructExactlyOne executes the query and retrieves the single result.
+	// Returns ErrNotFound if there are no rows, or ErrTooManyRows if there is more than one row.
+	StructExactlyOne(ctx context.Context, args ...any) (*T, error)
+
 	// Structs executes the query and retrieves multiple results, or an error if the query fails.
 	Structs(ctx context.Context, args ...any) ([]T, error)
+
+	// Value executes the query and scans the single column of the first row into a scalar T (e.g. int64, string).
+	// Returns nil, nil if there are no rows, or ErrNotFound in strict mode.
+	Value(ctx context.Context, args ...any) (*T, error)
+
+	// Values executes the query and scans the single column of all rows into a slice of scalar T.
+	Values(ctx context.Context, args ...any) ([]T, error)
+
+	// Each executes the query and calls fn for each result row by row, stopping at the first error.
+	// Rows are always closed before Each returns.
+	Each(ctx context.Context, fn func(T) error, args ...any) error
+
+	// Iter executes the query and returns an iterator that scans results row by row.
+	// Rows are closed when the iteration completes, fails or stops early.
+	Iter(ctx context.Context, args ...any) iter.Seq2[T, error]
+
+	// Maps executes the query and returns each row as a map of column names to values.
+	Maps(ctx context.Context, args ...any) ([]map[string]any, error)
 }
 
 type finder[T any] struct {
 	db           Readable
+	mapper       *mapper.Mapper
 	sql          string
 	replacements []string
 	transformers []func(*T) error
+	strict       bool
+	preloads     []preload
 }
 
 func (f *finder[T]) Query(s string) Finder[T] {
@@ -56,6 +94,20 @@ func (f *finder[T]) Replace(o, n string) Finder[T] {
 	return f
 }
 
+func (f *finder[T]) Preload(field string, relation ...Relation) Finder[T] {
+	p := preload{field: field}
+	if len(relation) > 0 {
+		p.relation = &relation[0]
+	}
+	f.preloads = append(f.preloads, p)
+	return f
+}
+
+func (f *finder[T]) Strict() Finder[T] {
+	f.strict = true
+	return f
+}
+
 func (f *finder[T]) WithTransformer(t func(*T) error) Finder[T] {
 	f.transformers = append(f.transformers, t)
 	return f
@@ -72,13 +124,52 @@ func (f *finder[T]) Rows(ctx context.Context, args ...any) (pgx.Rows, error) {
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, classify(err)
 	}
 
 	return rows, nil
 }
 
 func (f *finder[T]) Struct(ctx context.Context, args ...any) (*T, error) {
+	for result, err := range f.Iter(ctx, args...) {
+		if err != nil {
+			return nil, err
+		}
+
+		records := []T{result}
+		if err := f.preload(ctx, records); err != nil {
+			return nil, err
+		}
+		return &records[0], nil
+	}
+	return nil, f.notFound()
+}
+
+func (f *finder[T]) StructExactlyOne(ctx context.Context, args ...any) (*T, error) {
+	var found *T
+	for result, err := range f.Iter(ctx, args...) {
+		if err != nil {
+			return nil, err
+		}
+
+		if found != nil {
+			return nil, ErrTooManyRows
+		}
+		found = &result
+	}
+
+	if found == nil {
+		return nil, ErrNotFound
+	}
+
+	records := []T{*found}
+	if err := f.preload(ctx, records); err != nil {
+		return nil, err
+	}
+	return &records[0], nil
+}
+
+func (f *finder[T]) Structs(ctx context.Context, args ...any) ([]T, error) {
 	if !isStruct[T]() {
 		return nil, ErrStructOnly
 	}
@@ -87,34 +178,56 @@ func (f *finder[T]) Struct(ctx context.Context, args ...any) (*T, error) {
 	if err != nil {
 		return nil, err
 	} else if rows == nil {
-		return nil, nil
+		return []T{}, nil
 	}
 
-	result, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[T])
+	results, err := pgx.CollectRows(rows, rowToStruct[T](f.mapper))
 	if err != nil {
-		return nil, err
+		return nil, classify(err)
 	}
 
-	if tr, ok := any(&result).(Transformer); ok {
-		if err := tr.Transform(); err != nil {
+	for i := range results {
+		if err := f.transform(&results[i]); err != nil {
 			return nil, err
 		}
 	}
 
-	for _, transformer := range f.transformers {
-		if err := transformer(&result); err != nil {
-			return nil, err
-		}
+	if err := f.preload(ctx, results); err != nil {
+		return nil, err
 	}
 
-	return &result, nil
+	return results, nil
 }
 
-func (f *finder[T]) Structs(ctx context.Context, args ...any) ([]T, error) {
-	if !isStruct[T]() {
-		return nil, ErrStructOnly
+func (f *finder[T]) Value(ctx context.Context, args ...any) (*T, error) {
+	rows, err := f.Rows(ctx, args...)
+	if err != nil {
+		return nil, err
+	} else if rows == nil {
+		return nil, f.notFound()
 	}
+	defer rows.Close()
 
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, classify(err)
+		}
+		return nil, f.notFound()
+	}
+
+	var result T
+	if err := rows.Scan(&result); err != nil {
+		return nil, err
+	}
+
+	if err := f.transform(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (f *finder[T]) Values(ctx context.Context, args ...any) ([]T, error) {
 	rows, err := f.Rows(ctx, args...)
 	if err != nil {
 		return nil, err
@@ -122,24 +235,128 @@ func (f *finder[T]) Structs(ctx context.Context, args ...any) ([]T, error) {
 		return []T{}, nil
 	}
 
-	results, err := pgx.CollectRows(rows, pgx.RowToStructByName[T])
+	results, err := pgx.CollectRows(rows, pgx.RowTo[T])
 	if err != nil {
-		return nil, err
+		return nil, classify(err)
 	}
 
 	for i := range results {
-		if tr, ok := any(&results[i]).(Transformer); ok {
-			if err := tr.Transform(); err != nil {
-				return nil, err
-			}
+		if err := f.transform(&results[i]); err != nil {
+			return nil, err
 		}
+	}
+
+	return results, nil
+}
+
+func (f *finder[T]) Each(ctx context.Context, fn func(T) error, args ...any) error {
+	for result, err := range f.Iter(ctx, args...) {
+		if err != nil {
+			return err
+		}
+
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *finder[T]) Iter(ctx context.Context, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if !isStruct[T]() {
+			yield(zero, ErrStructOnly)
+			return
+		}
+
+		rows, err := f.Rows(ctx, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		} else if rows == nil {
+			return
+		}
+		defer rows.Close()
+
+		scan := rowToStruct[T](f.mapper)
+		for rows.Next() {
+			result, err := scan(rows)
+			if err == nil {
+				err = f.transform(&result)
+			}
+
+			if err != nil {
+				yield(zero, err)
+				return
+			}
 
-		for _, transformer := range f.transformers {
-			if err := transformer(&results[i]); err != nil {
-				return nil, err
+			if !yield(result, nil) {
+				return
 			}
 		}
+
+		if err := rows.Err(); err != nil {
+			yield(zero, classify(err))
+		}
 	}
+}
 
+func (f *finder[T]) Maps(ctx context.Context, args ...any) ([]map[string]any, error) {
+	rows, err := f.Rows(ctx, args...)
+	if err != nil {
+		return nil, err
+	} else if rows == nil {
+		return []map[string]any{}, nil
+	}
+
+	results, err := pgx.CollectRows(rows, pgx.RowToMap)
+	if err != nil {
+		return nil, classify(err)
+	}
 	return results, nil
 }
+
+// transform applies the Transformer interface of the record, including pointer records,
+// followed by the transformer functions.
+func (f *finder[T]) transform(v *T) error {
+	if tr, ok := any(v).(Transformer); ok {
+		if err := tr.Transform(); err != nil {
+			return err
+		}
+	} else if tr, ok := any(*v).(Transformer); ok {
+		if err := tr.Transform(); err != nil {
+			return err
+		}
+	}
+
+	for _, transformer := range f.transformers {
+		if err := transformer(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preload loads the requested relations of the records.
+func (f *finder[T]) preload(ctx context.Context, records []T) error {
+	for _, p := range f.preloads {
+		err := f.mapper.Preload(reflect.ValueOf(records), p.field, p.relation,
+			func(typ reflect.Type, table, column string, keys []any) ([]reflect.Value, error) {
+				return loadRelated(ctx, f.db, f.mapper, typ, table, column, keys)
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notFound returns the result error of Struct and Value when the query returns no rows.
+func (f *finder[T]) notFound() error {
+	if f.strict {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mekramy/gosql/internal/hook"
+	"github.com/mekramy/gosql/internal/mapper"
+)
+
+// Hook observes the database calls of a Queryable wrapped by WithHooks.
+type Hook = hook.Hook
+
+// QueryEvent describes an observed database call.
+type QueryEvent = hook.Event
+
+// Operation is the kind of database call observed by a hook.
+type Operation = hook.Operation
+
+const (
+	OpExec     = hook.OpExec     // Exec
+	OpQuery    = hook.OpQuery    // Query, observed until the rows are closed or consumed
+	OpQueryRow = hook.OpQueryRow // QueryRow, observed until the row is scanned
+	OpCopy     = hook.OpCopy     // CopyFrom
+)
+
+// WithHooks wraps the database (e.g. Connection.Executor(), *pgxpool.Pool, pgx.Tx) so that all builders
+// and repositories created with it run the hooks around each database call.
+// Hooks added to the call context with ContextWithHooks run after these hooks.
+func WithHooks(db Queryable, hooks ...Hook) Queryable {
+	if hooked, ok := db.(*hookedQueryable); ok {
+		return &hookedQueryable{
+			db:    hooked.db,
+			hooks: append(hooked.hooks[:len(hooked.hooks):len(hooked.hooks)], hooks...),
+		}
+	}
+	return &hookedQueryable{db: db, hooks: hooks}
+}
+
+// ContextWithHooks returns a copy of ctx carrying per call hooks, which run on the database calls
+// of Connection.Executor(), Cluster.Executor() and Queryables wrapped by WithHooks.
+// Pools and transactions used directly (e.g. Connection.Database(), the Transaction callback) don't run them.
+func ContextWithHooks(ctx context.Context, hooks ...Hook) context.Context {
+	return hook.WithContext(ctx, hooks...)
+}
+
+// SlowQueryLogger returns a hook logging the database calls that take at least threshold as warnings,
+// using slog.Default() if logger is nil. Arguments are redacted, only their count is logged.
+func SlowQueryLogger(logger *slog.Logger, threshold time.Duration) Hook {
+	return hook.SlowQueryLogger(logger, threshold)
+}
+
+type hookedQueryable struct {
+	db    Queryable
+	hooks []Hook
+}
+
+func (h *hookedQueryable) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	ctx, finish := hook.Start(ctx, h.hooks, OpExec, sql, args)
+	tag, err := h.db.Exec(ctx, sql, args...)
+	finish(tag.RowsAffected(), err)
+	return tag, err
+}
+
+func (h *hookedQueryable) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ctx, finish := hook.Start(ctx, h.hooks, OpQuery, sql, args)
+	rows, err := h.db.Query(ctx, sql, args...)
+	if err != nil {
+		finish(0, err)
+		return nil, err
+	}
+	return &hookedRows{Rows: rows, finish: finish}, nil
+}
+
+func (h *hookedQueryable) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ctx, finish := hook.Start(ctx, h.hooks, OpQueryRow, sql, args)
+	return &hookedRow{row: h.db.QueryRow(ctx, sql, args...), finish: finish}
+}
+
+func (h *hookedQueryable) CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error) {
+	copier, ok := h.db.(Copyable)
+	if !ok {
+		return 0, ErrCopyUnsupported
+	}
+
+	ctx, finish := hook.Start(ctx, h.hooks, OpCopy, table.Sanitize(), nil)
+	count, err := copier.CopyFrom(ctx, table, columns, src)
+	finish(count, err)
+	return count, err
+}
+
+func (h *hookedQueryable) structMapper() *mapper.Mapper {
+	return mapperOf(h.db)
+}
+
+// hookedRows runs the After hooks once the rows are consumed or closed.
+type hookedRows struct {
+	pgx.Rows
+	finish hook.Finish
+	count  int64
+}
+
+func (r *hookedRows) Next() bool {
+	if r.Rows.Next() {
+		r.count++
+		return true
+	}
+
+	r.finish(r.count, r.Rows.Err())
+	return false
+}
+
+func (r *hookedRows) Close() {
+	r.Rows.Close()
+	r.finish(r.count, r.Rows.Err())
+}
+
+// hookedRow runs the After hooks once the row is scanned.
+type hookedRow struct {
+	row    pgx.Row
+	finish hook.Finish
+}
+
+func (r *hookedRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if err != nil {
+		r.finish(0, err)
+	} else {
+		r.finish(1, nil)
+	}
+	return err
+}
@@ -3,15 +3,20 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mekramy/gosql/internal/mapper"
 )
 
 // NewInserter initializes and returns a new Inserter instance for the given Executable interface.
 func NewInserter[T any](e Executable) Inserter[T] {
 	return &inserter[T]{
-		db: e,
+		db:       e,
+		mapper:   mapperOf(e),
+		conflict: make([]string, 0),
 		option: &options{
 			only:    make([]string, 0),
 			exclude: make([]string, 0),
@@ -24,14 +29,50 @@ type Inserter[T any] interface {
 	// Table sets the target table for the insert operation.
 	Table(table string) Inserter[T]
 
+	// OnConflict sets the conflict target columns used by Upsert.
+	OnConflict(columns ...string) Inserter[T]
+
+	// ConflictWhere specifies an optional WHERE condition for the DO UPDATE clause of Upsert.
+	ConflictWhere(condition string, args ...any) Inserter[T]
+
+	// DoNothing makes Upsert ignore conflicting rows instead of updating them.
+	DoNothing() Inserter[T]
+
+	// Returning adds a RETURNING clause to Insert and Upsert and scans the returned
+	// columns into the matching `db` tagged fields of dest (e.g. generated id, defaults).
+	Returning(dest *T, columns ...string) Inserter[T]
+
 	// Insert performs an insert operation with the given record and options.
 	Insert(ctx context.Context, record T, options ...RepositoryOption) (pgconn.CommandTag, error)
+
+	// InsertMany inserts the given records using multi-row INSERT statements.
+	// Records are chunked to stay below the PostgreSQL parameter limit, run it inside a transaction for atomicity.
+	// Returns the total number of inserted rows.
+	InsertMany(ctx context.Context, records []T, options ...RepositoryOption) (int64, error)
+
+	// CopyFrom inserts the given records using the PostgreSQL COPY protocol.
+	// The underlying Executable must support CopyFrom (e.g. *pgxpool.Pool, pgx.Tx).
+	// `default` and `omitempty` fields are left to the database default when they are zero in every record,
+	// and return ErrCopyDefault when they are zero in only some records (use InsertMany instead).
+	// Returns the total number of copied rows.
+	CopyFrom(ctx context.Context, records []T, options ...RepositoryOption) (int64, error)
+
+	// Upsert performs an insert operation and updates the existing row on conflict.
+	// Updated columns are derived from the record fields, excluding the conflict and pk columns.
+	Upsert(ctx context.Context, record T, options ...RepositoryOption) (pgconn.CommandTag, error)
 }
 
 type inserter[T any] struct {
-	db     Executable
-	table  string
-	option *options
+	db       Executable
+	mapper   *mapper.Mapper
+	table    string
+	conflict []string
+	where    string
+	args     []any
+	nothing  bool
+	dest     *T
+	returns  []string
+	option   *options
 }
 
 func (i *inserter[T]) Table(t string) Inserter[T] {
@@ -39,6 +80,28 @@ func (i *inserter[T]) Table(t string) Inserter[T] {
 	return i
 }
 
+func (i *inserter[T]) OnConflict(columns ...string) Inserter[T] {
+	i.conflict = append(i.conflict, columns...)
+	return i
+}
+
+func (i *inserter[T]) ConflictWhere(w string, args ...any) Inserter[T] {
+	i.where = w
+	i.args = append([]any{}, args...)
+	return i
+}
+
+func (i *inserter[T]) DoNothing() Inserter[T] {
+	i.nothing = true
+	return i
+}
+
+func (i *inserter[T]) Returning(dest *T, columns ...string) Inserter[T] {
+	i.dest = dest
+	i.returns = append([]string{}, columns...)
+	return i
+}
+
 func (i *inserter[T]) Insert(ctx context.Context, v T, options ...RepositoryOption) (pgconn.CommandTag, error) {
 	if !isStruct[T]() {
 		return pgconn.CommandTag{}, ErrStructOnly
@@ -52,20 +115,219 @@ func (i *inserter[T]) Insert(ctx context.Context, v T, options ...RepositoryOpti
 		opt(i.option)
 	}
 
-	columns := structColumns(v, i.option.only, i.option.exclude)
-	values := structValues(v, i.option.only, i.option.exclude)
-	placeholders := make([]string, 0, len(columns))
+	columns, values := structFields(i.mapper, v, mapper.Insert, i.option.only, i.option.exclude)
+
+	sql := fmt.Sprintf(`%s;`, i.insertSQL(columns))
+	return i.exec(ctx, sql, values...)
+}
+
+func (i *inserter[T]) InsertMany(ctx context.Context, records []T, options ...RepositoryOption) (int64, error) {
+	if !isStruct[T]() {
+		return 0, ErrStructOnly
+	}
 
+	if i.table == "" {
+		return 0, ErrEmptySQL
+	}
+
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	for _, opt := range options {
+		opt(i.option)
+	}
+
+	columns, _ := structFields(i.mapper, records[0], mapper.Bulk, i.option.only, i.option.exclude)
+	if len(columns) == 0 {
+		return 0, ErrEmptySQL
+	}
+
+	var affected int64
+	size := batchSize(len(columns), i.option.batch)
+	for start := 0; start < len(records); start += size {
+		end := min(start+size, len(records))
+		rows := make([]string, 0, end-start)
+		values := make([]any, 0, (end-start)*len(columns))
+
+		for _, record := range records[start:end] {
+			placeholders := make([]string, 0, len(columns))
+			_, fields := structFields(i.mapper, record, mapper.Bulk, i.option.only, i.option.exclude)
+			for _, value := range fields {
+				if _, ok := value.(mapper.Default); ok {
+					placeholders = append(placeholders, "DEFAULT")
+				} else {
+					values = append(values, value)
+					placeholders = append(placeholders, fmt.Sprintf("$%d", len(values)))
+				}
+			}
+			rows = append(rows, "("+strings.Join(placeholders, ",")+")")
+		}
+
+		sql := fmt.Sprintf(
+			`INSERT INTO %s (%s) VALUES %s;`,
+			quoteTable(i.table),
+			strings.Join(columns, ","),
+			strings.Join(rows, ","),
+		)
+
+		tag, err := i.db.Exec(ctx, sql, values...)
+		if err != nil {
+			return affected, classify(err)
+		}
+		affected += tag.RowsAffected()
+	}
+
+	return affected, nil
+}
+
+func (i *inserter[T]) CopyFrom(ctx context.Context, records []T, options ...RepositoryOption) (int64, error) {
+	if !isStruct[T]() {
+		return 0, ErrStructOnly
+	}
+
+	if i.table == "" {
+		return 0, ErrEmptySQL
+	}
+
+	copier, ok := i.db.(Copyable)
+	if !ok {
+		return 0, ErrCopyUnsupported
+	}
+
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	for _, opt := range options {
+		opt(i.option)
+	}
+
+	// COPY has no DEFAULT keyword, so columns left to the database default
+	// in every record are excluded and mixed columns are rejected.
+	var columns []string
+	rows := make([][]any, 0, len(records))
+	for _, record := range records {
+		var values []any
+		columns, values = structFields(i.mapper, record, mapper.Bulk, i.option.only, i.option.exclude)
+		rows = append(rows, values)
+	}
+
+	copied := make([]string, 0, len(columns))
+	indexes := make([]int, 0, len(columns))
+	for idx, col := range columns {
+		defaults := 0
+		for _, values := range rows {
+			if _, ok := values[idx].(mapper.Default); ok {
+				defaults++
+			}
+		}
+
+		switch defaults {
+		case 0:
+			copied = append(copied, strings.Trim(col, `"`))
+			indexes = append(indexes, idx)
+		case len(rows):
+			continue
+		default:
+			return 0, fmt.Errorf("%w: %s", ErrCopyDefault, strings.Trim(col, `"`))
+		}
+	}
+
+	count, err := copier.CopyFrom(
+		ctx,
+		pgx.Identifier(strings.Split(i.table, ".")),
+		copied,
+		pgx.CopyFromSlice(len(rows), func(idx int) ([]any, error) {
+			values := make([]any, 0, len(indexes))
+			for _, col := range indexes {
+				values = append(values, rows[idx][col])
+			}
+			return values, nil
+		}),
+	)
+	return count, classify(err)
+}
+
+func (i *inserter[T]) Upsert(ctx context.Context, v T, options ...RepositoryOption) (pgconn.CommandTag, error) {
+	if !isStruct[T]() {
+		return pgconn.CommandTag{}, ErrStructOnly
+	}
+
+	if i.table == "" {
+		return pgconn.CommandTag{}, ErrEmptySQL
+	}
+
+	for _, opt := range options {
+		opt(i.option)
+	}
+
+	columns, values := structFields(i.mapper, v, mapper.Insert, i.option.only, i.option.exclude)
+
+	// Resolve conflict target
+	target := ""
+	if len(i.conflict) > 0 {
+		quoted := make([]string, 0, len(i.conflict))
+		for _, col := range i.conflict {
+			quoted = append(quoted, quoteField(col))
+		}
+		target = fmt.Sprintf(" (%s)", strings.Join(quoted, ","))
+	}
+
+	// Resolve update set, skipping conflict and primary key columns
+	keys := slices.Concat(primaryKeys[T](i.mapper), i.conflict)
+	updates := make([]string, 0, len(columns))
+	for _, col := range columns {
+		if !slices.Contains(keys, strings.Trim(col, `"`)) {
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+		}
+	}
+
+	if i.nothing || len(updates) == 0 {
+		sql := fmt.Sprintf(`%s ON CONFLICT%s DO NOTHING;`, i.insertSQL(columns), target)
+		return i.exec(ctx, sql, values...)
+	}
+
+	if target == "" {
+		return pgconn.CommandTag{}, ErrEmptyConflict
+	}
+
+	sql := fmt.Sprintf(
+		`%s ON CONFLICT%s DO UPDATE SET %s`,
+		i.insertSQL(columns),
+		target,
+		strings.Join(updates, ","),
+	)
+	if i.where != "" {
+		sql = fmt.Sprintf("%s WHERE %s", sql, normalizePlaceholderFrom(i.where, len(values)))
+		values = append(values, i.args...)
+	}
+
+	return i.exec(ctx, sql+";", values...)
+}
+
+// exec executes the command, appending the RETURNING clause and scanning the result if requested.
+func (i *inserter[T]) exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if i.dest == nil || len(i.returns) == 0 {
+		tag, err := i.db.Exec(ctx, sql, args...)
+		return tag, classify(err)
+	}
+
+	sql = strings.TrimSuffix(sql, ";") + returningClause(i.returns) + ";"
+	return execReturning(ctx, i.db, sql, i.dest, i.returns, args...)
+}
+
+// insertSQL generates the INSERT INTO statement for the given columns without a trailing semicolon.
+func (i *inserter[T]) insertSQL(columns []string) string {
+	placeholders := make([]string, 0, len(columns))
 	for idx := range columns {
 		placeholders = append(placeholders, fmt.Sprintf("$%d", idx+1))
 	}
 
-	sql := fmt.Sprintf(
-		`INSERT INTO "%s" (%s) VALUES (%s);`,
-		i.table,
+	return fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s)`,
+		quoteTable(i.table),
 		strings.Join(columns, ","),
 		strings.Join(placeholders, ","),
 	)
-
-	return i.db.Exec(ctx, sql, values...)
 }
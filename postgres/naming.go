@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mekramy/gosql/internal/mapper"
+)
+
+// NameMapper converts an untagged struct field name to its column name.
+type NameMapper func(field string) string
+
+// Built-in name mappers for untagged struct fields.
+var (
+	SnakeCase NameMapper = mapper.SnakeCase                           // UserID to user_id
+	CamelCase NameMapper = mapper.CamelCase                           // UserID to userID
+	ExactCase NameMapper = func(field string) string { return field } // UserID to UserID
+)
+
+// defaultMapper is the struct mapper used by executors without a name mapper.
+var defaultMapper atomic.Pointer[mapper.Mapper]
+
+func init() {
+	defaultMapper.Store(mapper.New(quoteField, nil))
+}
+
+// SetNameMapper sets the package wide name mapper for untagged struct fields.
+// Untagged fields are written and read using the mapped column name, a nil mapper restores
+// the default behavior where untagged fields are only matched loosely on reads.
+// Call it once at startup, since struct mappings are cached per name mapper.
+func SetNameMapper(names NameMapper) {
+	defaultMapper.Store(mapper.New(quoteField, names))
+}
+
+// WithNameMapper wraps the database (e.g. *pgxpool.Pool, pgx.Tx) so that all
+// builders and repositories created with it map untagged struct fields using `names`.
+// Wrap the database once and reuse it, since struct mappings are cached per wrapper.
+// Transactions do not inherit the name mapper of the wrapped database: wrap Connection.Executor()
+// and use TransactionContext, so builders created with the wrapper run on the context transaction,
+// or wrap the transaction passed to a Connection.Transaction callback again.
+func WithNameMapper(db Queryable, names NameMapper) Queryable {
+	return &namedQueryable{
+		Queryable: db,
+		mapper:    mapper.New(quoteField, names),
+	}
+}
+
+type namedQueryable struct {
+	Queryable
+	mapper *mapper.Mapper
+}
+
+func (n *namedQueryable) CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error) {
+	copier, ok := n.Queryable.(Copyable)
+	if !ok {
+		return 0, ErrCopyUnsupported
+	}
+	return copier.CopyFrom(ctx, table, columns, src)
+}
+
+func (n *namedQueryable) structMapper() *mapper.Mapper {
+	return n.mapper
+}
+
+// mapperOf returns the struct mapper of the database, or the package default.
+func mapperOf(db any) *mapper.Mapper {
+	if named, ok := db.(interface{ structMapper() *mapper.Mapper }); ok {
+		return named.structMapper()
+	}
+	return defaultMapper.Load()
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mekramy/gosql/internal/mapper"
+)
+
+// NewRepository creates a new Repository instance for the given table.
+// Primary key columns are resolved from the fields tagged with the `pk` option (e.g. `db:"id,pk"`).
+// If T has a field tagged with the `softdelete` option, soft deleted records are excluded from reads.
+func NewRepository[T any](db Queryable, table string) Repository[T] {
+	return &repository[T]{
+		db:     db,
+		mapper: mapperOf(db),
+		table:  table,
+	}
+}
+
+// Repository provides primary key aware CRUD methods for a database table.
+type Repository[T any] interface {
+	// FindByID retrieves a record by its primary key values, in the pk fields order.
+	FindByID(ctx context.Context, ids ...any) (*T, error)
+
+	// FindAll retrieves the records matching the condition, or all records if cond is nil.
+	// Conditions must use '?' placeholders (e.g. query.NewCondition() without resolver).
+	FindAll(ctx context.Context, cond Condition) ([]T, error)
+
+	// Create inserts the record with the provided options.
+	Create(ctx context.Context, record T, options ...RepositoryOption) (pgconn.CommandTag, error)
+
+	// Update replaces the record identified by its primary key values.
+	// Every written column is set, including zero values, except the pk, readonly and zero omitempty fields.
+	// Use OnlyFields for partial updates. Soft deleted records are not updated.
+	Update(ctx context.Context, record T, options ...RepositoryOption) (pgconn.CommandTag, error)
+
+	// Delete deletes the record identified by the primary key values.
+	// Records are soft deleted if T has a field tagged with the `softdelete` option.
+	Delete(ctx context.Context, ids ...any) (pgconn.CommandTag, error)
+
+	// Exists checks whether a record with the given primary key values exists.
+	Exists(ctx context.Context, ids ...any) (bool, error)
+
+	// Count returns the number of records matching the condition, or all records if cond is nil.
+	Count(ctx context.Context, cond Condition) (int64, error)
+}
+
+type repository[T any] struct {
+	db     Queryable
+	mapper *mapper.Mapper
+	table  string
+}
+
+func (r *repository[T]) FindByID(ctx context.Context, ids ...any) (*T, error) {
+	where, err := r.keyCondition(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFinder[T](r.db).
+		Query(fmt.Sprintf(`SELECT %s FROM %s %s LIMIT 1;`, r.columns(), quoteTable(r.table), r.scope(where))).
+		Struct(ctx, ids...)
+}
+
+func (r *repository[T]) FindAll(ctx context.Context, cond Condition) ([]T, error) {
+	where, args := r.condition(cond)
+	return NewFinder[T](r.db).
+		Query(fmt.Sprintf(`SELECT %s FROM %s %s;`, r.columns(), quoteTable(r.table), r.scope(where))).
+		Structs(ctx, args...)
+}
+
+func (r *repository[T]) Create(ctx context.Context, v T, options ...RepositoryOption) (pgconn.CommandTag, error) {
+	return NewInserter[T](r.db).
+		Table(r.table).
+		Insert(ctx, v, options...)
+}
+
+func (r *repository[T]) Update(ctx context.Context, v T, options ...RepositoryOption) (pgconn.CommandTag, error) {
+	keys := primaryKeys[T](r.mapper)
+	_, ids := structFields(r.mapper, v, mapper.Select, keys, nil)
+	where, err := r.keyCondition(ids)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	return NewUpdater[T](r.db).
+		Table(r.table).
+		Where(r.active(where), ids...).
+		Update(ctx, v, options...)
+}
+
+func (r *repository[T]) Delete(ctx context.Context, ids ...any) (pgconn.CommandTag, error) {
+	where, err := r.keyCondition(ids)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	return NewDeleter[T](r.db).
+		Table(r.table).
+		Where(where, ids...).
+		Delete(ctx)
+}
+
+func (r *repository[T]) Exists(ctx context.Context, ids ...any) (bool, error) {
+	if !isStruct[T]() {
+		return false, ErrStructOnly
+	}
+
+	where, err := r.keyCondition(ids)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	sql := compile(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s %s);`, quoteTable(r.table), r.scope(where)))
+	if err := r.db.QueryRow(ctx, sql, ids...).Scan(&exists); err != nil {
+		return false, classify(err)
+	}
+	return exists, nil
+}
+
+func (r *repository[T]) Count(ctx context.Context, cond Condition) (int64, error) {
+	if !isStruct[T]() {
+		return 0, ErrStructOnly
+	}
+
+	where, args := r.condition(cond)
+	return NewCounter(r.db).
+		Query(fmt.Sprintf(`SELECT COUNT(*) FROM %s %s;`, quoteTable(r.table), r.scope(where))).
+		Count(ctx, args...)
+}
+
+// columns returns the quoted select list of T.
+func (r *repository[T]) columns() string {
+	if meta := metadataOf[T](r.mapper); meta != nil {
+		return strings.Join(meta.Columns, ",")
+	}
+	return "*"
+}
+
+// keyCondition generates the primary key condition for the given key values.
+func (r *repository[T]) keyCondition(ids []any) (string, error) {
+	keys := primaryKeys[T](r.mapper)
+	if len(keys) == 0 {
+		return "", ErrNoPrimaryKey
+	}
+
+	if len(ids) != len(keys) {
+		return "", ErrPrimaryKeyMismatch
+	}
+
+	conditions := make([]string, 0, len(keys))
+	for _, key := range keys {
+		conditions = append(conditions, fmt.Sprintf("%s = ?", quoteField(key)))
+	}
+	return strings.Join(conditions, " AND "), nil
+}
+
+// condition extracts the SQL and arguments of a nullable condition.
+func (r *repository[T]) condition(cond Condition) (string, []any) {
+	if cond == nil {
+		return "", nil
+	}
+	return cond.SQL(), cond.Arguments()
+}
+
+// scope generates the WHERE clause for the condition, excluding soft deleted records.
+func (r *repository[T]) scope(where string) string {
+	where = r.active(where)
+	if where == "" {
+		return ""
+	}
+	return "WHERE " + where
+}
+
+// active extends the condition to exclude soft deleted records.
+func (r *repository[T]) active(where string) string {
+	column := softDeleteColumn[T](r.mapper)
+	if column == "" {
+		return where
+	}
+
+	if where == "" {
+		return fmt.Sprintf("%s IS NULL", quoteField(column))
+	}
+	return fmt.Sprintf("(%s) AND %s IS NULL", where, quoteField(column))
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mekramy/gosql/internal/retry"
+	"github.com/mekramy/gosql/internal/txhook"
+	"github.com/mekramy/gosql/internal/txopt"
+)
+
+// RetryPolicy configures how TransactionWithRetry re-runs a transaction.
+// Zero fields fall back to the defaults (3 attempts, 10ms base delay, 1s max delay).
+type RetryPolicy = retry.Policy
+
+// TxOptions configures the isolation level and access mode of a transaction.
+type TxOptions = txopt.Options
+
+// IsolationLevel specifies the transaction isolation level.
+type IsolationLevel = txopt.IsolationLevel
+
+// Supported isolation levels.
+const (
+	LevelDefault         = txopt.Default
+	LevelReadUncommitted = txopt.ReadUncommitted
+	LevelReadCommitted   = txopt.ReadCommitted
+	LevelRepeatableRead  = txopt.RepeatableRead
+	LevelSerializable    = txopt.Serializable
+)
+
+// transactions tracks the hooks of the active transactions.
+var transactions txhook.Registry
+
+// txKey is the context key of the enclosing transaction.
+type txKey struct{}
+
+// WithTx returns a copy of ctx carrying the transaction.
+// Connection.Executor runs commands on tx with the returned context, and Connection.Transaction
+// calls with it run as nested transactions of tx using savepoints.
+func WithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction stored in ctx by WithTx, or nil.
+func TxFromContext(ctx context.Context) pgx.Tx {
+	tx, _ := ctx.Value(txKey{}).(pgx.Tx)
+	return tx
+}
+
+// isRetryable checks if the error is a serialization failure (40001) or a deadlock (40P01).
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == "40001" || pgErr.Code == "40P01")
+}
+
+// OnCommit registers fn to run after the transaction commits.
+// Callbacks of nested transactions run after the outermost transaction commits.
+// Returns ErrNoTransaction if tx is not an active transaction of Connection.Transaction.
+func OnCommit(tx pgx.Tx, fn func()) error {
+	hooks := transactions.Lookup(tx)
+	if hooks == nil {
+		return ErrNoTransaction
+	}
+
+	hooks.OnCommit(fn)
+	return nil
+}
+
+// OnRollback registers fn to run after the transaction, or the nested transaction, rolls back.
+// Returns ErrNoTransaction if tx is not an active transaction of Connection.Transaction.
+func OnRollback(tx pgx.Tx, fn func()) error {
+	hooks := transactions.Lookup(tx)
+	if hooks == nil {
+		return ErrNoTransaction
+	}
+
+	hooks.OnRollback(fn)
+	return nil
+}
+
+// OnCommitContext registers fn to run after the transaction stored in ctx commits.
+func OnCommitContext(ctx context.Context, fn func()) error {
+	return OnCommit(TxFromContext(ctx), fn)
+}
+
+// OnRollbackContext registers fn to run after the transaction stored in ctx rolls back.
+func OnRollbackContext(ctx context.Context, fn func()) error {
+	return OnRollback(TxFromContext(ctx), fn)
+}
+
+// pgxTxOptions converts the transaction options to pgx.TxOptions.
+func pgxTxOptions(opts TxOptions) pgx.TxOptions {
+	var options pgx.TxOptions
+	switch opts.Isolation {
+	case txopt.ReadUncommitted:
+		options.IsoLevel = pgx.ReadUncommitted
+	case txopt.ReadCommitted:
+		options.IsoLevel = pgx.ReadCommitted
+	case txopt.RepeatableRead:
+		options.IsoLevel = pgx.RepeatableRead
+	case txopt.Serializable:
+		options.IsoLevel = pgx.Serializable
+	}
+
+	if opts.ReadOnly {
+		options.AccessMode = pgx.ReadOnly
+	}
+
+	if opts.Deferrable {
+		options.DeferrableMode = pgx.Deferrable
+	}
+	return options
+}
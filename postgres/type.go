@@ -6,12 +6,42 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mekramy/gosql/internal/mapper"
+	"github.com/mekramy/gosql/internal/pool"
+	"github.com/mekramy/gosql/internal/sqlerr"
 )
 
 // Commonly used errors for database operations.
 var (
-	ErrEmptySQL   = errors.New("SQL command cannot be empty")
-	ErrStructOnly = errors.New("expected type must be a struct")
+	ErrEmptySQL             = errors.New("SQL command cannot be empty")
+	ErrUnsupportedCounter   = errors.New("counter must be created by NewCounter")
+	ErrStructOnly           = errors.New("expected type must be a struct")
+	ErrEmptyConflict        = errors.New("conflict target cannot be empty")
+	ErrCopyUnsupported      = errors.New("executable does not support copy from")
+	ErrCopyDefault          = errors.New("copy from cannot mix default and explicit values in a column")
+	ErrReturningUnsupported = errors.New("executable does not support returning")
+	ErrUnknownColumn        = mapper.ErrUnknownColumn
+	ErrNotFound             = sqlerr.ErrNotFound
+	ErrTooManyRows          = sqlerr.ErrTooManyRows
+	ErrInvalidRelation      = mapper.ErrInvalidRelation
+	ErrNoPrimaryKey         = errors.New("expected fields tagged with pk")
+	ErrPrimaryKeyMismatch   = errors.New("expected a value for each primary key")
+	ErrNoSoftDelete         = errors.New("expected a field tagged with softdelete")
+	ErrNoTransaction        = errors.New("expected an active transaction")
+	ErrInvalidDSN           = errors.New("invalid DSN")
+	ErrNilConfig            = errors.New("config resolver returned a nil config")
+	ErrManagerClosed        = pool.ErrClosed
+)
+
+// Classified database errors returned by all executors, see SQLError.
+var (
+	ErrUniqueViolation     = sqlerr.ErrUniqueViolation
+	ErrForeignKeyViolation = sqlerr.ErrForeignKeyViolation
+	ErrNotNullViolation    = sqlerr.ErrNotNullViolation
+	ErrCheckViolation      = sqlerr.ErrCheckViolation
+	ErrDeadlock            = sqlerr.ErrDeadlock
+	ErrSerialization       = sqlerr.ErrSerialization
+	ErrLockTimeout         = sqlerr.ErrLockTimeout
 )
 
 // Transformer defines an interface for decoding and transforming data.
@@ -28,6 +58,22 @@ type Executable interface {
 	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
 }
 
+// Copyable defines an interface for bulk loading rows using the PostgreSQL COPY protocol.
+type Copyable interface {
+	// CopyFrom copies rows from the source into the given table and columns.
+	// Returns the number of copied rows.
+	CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error)
+}
+
+// Condition defines an interface for dynamically built SQL conditions, such as query.ConditionBuilder.
+type Condition interface {
+	// SQL returns the conditions as a raw SQL string with '?' placeholders.
+	SQL() string
+
+	// Arguments returns the list of arguments associated with the conditions.
+	Arguments() []any
+}
+
 // Readable defines an interface for executing SQL queries.
 type Readable interface {
 	// Query runs a SQL query with optional parameters and returns a pgx.Rows iterator.
@@ -38,3 +84,9 @@ type Readable interface {
 	// The provided context is used for managing timeouts and cancellations.
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
+
+// Queryable defines an interface for both executing SQL commands and queries.
+type Queryable interface {
+	Executable
+	Readable
+}
@@ -6,12 +6,14 @@ import (
 	"strings"
 
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mekramy/gosql/internal/mapper"
 )
 
 // NewUpdater initializes and returns a new Updater instance for the given Executable interface.
 func NewUpdater[T any](e Executable) Updater[T] {
 	return &updater[T]{
-		db: e,
+		db:     e,
+		mapper: mapperOf(e),
 		option: &options{
 			only:    make([]string, 0),
 			exclude: make([]string, 0),
@@ -27,16 +29,23 @@ type Updater[T any] interface {
 	// Where specifies the WHERE condition for the update.
 	Where(condition string, args ...any) Updater[T]
 
+	// Returning adds a RETURNING clause to the update and scans the returned
+	// columns of the first updated row into the matching `db` tagged fields of dest.
+	Returning(dest *T, columns ...string) Updater[T]
+
 	// Update performs the update operation with the provided record and options.
 	Update(ctx context.Context, record T, options ...RepositoryOption) (pgconn.CommandTag, error)
 }
 
 type updater[T any] struct {
-	db     Executable
-	table  string
-	where  string
-	args   []any
-	option *options
+	db      Executable
+	mapper  *mapper.Mapper
+	table   string
+	where   string
+	args    []any
+	dest    *T
+	returns []string
+	option  *options
 }
 
 func (u *updater[T]) Table(t string) Updater[T] {
@@ -50,6 +59,12 @@ func (u *updater[T]) Where(w string, args ...any) Updater[T] {
 	return u
 }
 
+func (u *updater[T]) Returning(dest *T, columns ...string) Updater[T] {
+	u.dest = dest
+	u.returns = append([]string{}, columns...)
+	return u
+}
+
 func (u *updater[T]) Update(ctx context.Context, v T, options ...RepositoryOption) (pgconn.CommandTag, error) {
 	if !isStruct[T]() {
 		return pgconn.CommandTag{}, ErrStructOnly
@@ -63,8 +78,11 @@ func (u *updater[T]) Update(ctx context.Context, v T, options ...RepositoryOptio
 		opt(u.option)
 	}
 
-	columns := structColumns(v, u.option.only, u.option.exclude)
-	values := structValues(v, u.option.only, u.option.exclude)
+	columns, values := structFields(u.mapper, v, mapper.Update, u.option.only, u.option.exclude)
+	if len(columns) == 0 {
+		return pgconn.CommandTag{}, ErrEmptySQL
+	}
+
 	values = append(values, u.args...)
 
 	for i, col := range columns {
@@ -72,12 +90,18 @@ func (u *updater[T]) Update(ctx context.Context, v T, options ...RepositoryOptio
 	}
 
 	sql := fmt.Sprintf(
-		`UPDATE "%s" SET %s WHERE %s;`,
-		u.table,
+		`UPDATE %s SET %s WHERE %s`,
+		quoteTable(u.table),
 		strings.Join(columns, ","),
 		u.where,
 	)
 	sql = normalizePlaceholder(sql)
 
-	return u.db.Exec(ctx, sql, values...)
+	if u.dest != nil && len(u.returns) > 0 {
+		sql = sql + returningClause(u.returns) + ";"
+		return execReturning(ctx, u.db, sql, u.dest, u.returns, values...)
+	}
+
+	tag, err := u.db.Exec(ctx, sql+";", values...)
+	return tag, classify(err)
 }
@@ -1,12 +1,19 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"reflect"
-	"slices"
 	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mekramy/gosql/internal/mapper"
 )
 
+// maxParams is the maximum number of bind parameters allowed in a single PostgreSQL statement.
+const maxParams = 65535
+
 // parseVariadic returns the first value from `vals` or the default value `def` if `vals` is empty.
 func parseVariadic[T any](def T, vals ...T) T {
 	if len(vals) > 0 {
@@ -15,11 +22,47 @@ func parseVariadic[T any](def T, vals ...T) T {
 	return def
 }
 
-// isStruct checks if the type of T is a struct.
+// batchSize returns the number of records per statement for bulk operations,
+// capped so that `columns` parameters per record stay below maxParams.
+func batchSize(columns, size int) int {
+	limit := max(maxParams/max(columns, 1), 1)
+	if size <= 0 || size > limit {
+		return limit
+	}
+	return size
+}
+
+// isStruct checks if the type of T is a struct or a pointer to a struct.
 func isStruct[T any](_ ...T) bool {
-	var v T
-	val := reflect.Indirect(reflect.ValueOf(v))
-	return val.Kind() == reflect.Struct
+	return structType[T]().Kind() == reflect.Struct
+}
+
+// structType returns the type of T, or the pointed type if T is a pointer.
+func structType[T any]() reflect.Type {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() == reflect.Pointer {
+		return typ.Elem()
+	}
+	return typ
+}
+
+// structValue dereferences v until it reaches a non pointer value.
+// Returns an invalid value if v is a nil pointer.
+func structValue(v any) reflect.Value {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Pointer {
+		val = val.Elem()
+	}
+	return val
+}
+
+// newRecord returns a new T, allocating the pointed struct if T is a pointer.
+func newRecord[T any]() T {
+	var result T
+	if typ := reflect.TypeFor[T](); typ.Kind() == reflect.Pointer {
+		result = reflect.New(typ.Elem()).Interface().(T)
+	}
+	return result
 }
 
 // compile replaces @placeholder in SQL query and converts '?' to numbered placeholders ($1, $2, ...).
@@ -27,67 +70,114 @@ func compile(query string, replacements ...string) string {
 	return normalizePlaceholder(strings.NewReplacer(replacements...).Replace(query))
 }
 
-// structColumns extracts column names from the `db` struct tag, skipping unexported fields.
-func structColumns(v any, only, exclude []string) []string {
-	val := reflect.Indirect(reflect.ValueOf(v))
+// metadataOf returns the cached column mapping of T, or nil if T is not a struct.
+func metadataOf[T any](m *mapper.Mapper) *mapper.Metadata {
+	return m.Of(structType[T]())
+}
+
+// structFields extracts the quoted column names and values of a struct for the given mode.
+// In bulk mode, fields resolved to DEFAULT are returned as mapper.Default.
+func structFields(m *mapper.Mapper, v any, mode mapper.Mode, only, exclude []string) ([]string, []any) {
+	val := structValue(v)
 	if val.Kind() != reflect.Struct {
-		return nil
+		return nil, nil
 	}
+	return m.Of(val.Type()).Resolve(val, mode, only, exclude)
+}
 
-	typ := val.Type()
-	columns := make([]string, 0, typ.NumField())
-
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
+// structPointers returns pointers to the struct fields matching the given columns, in order.
+// Columns match the `db` tag or mapped name, or the untagged field name ignoring case and underscores.
+func structPointers(m *mapper.Mapper, v any, columns []string) ([]any, error) {
+	val := structValue(v)
+	if val.Kind() != reflect.Struct || !val.CanAddr() {
+		return nil, ErrStructOnly
+	}
+	return m.Of(val.Type()).Pointers(val, columns)
+}
 
-		// Skip unexported fields.
-		if !field.IsExported() {
-			continue
+// rowToStruct returns a pgx.RowToFunc that scans the current row into a new T
+// using the same field mapping as the write operations.
+func rowToStruct[T any](m *mapper.Mapper) pgx.RowToFunc[T] {
+	return func(row pgx.CollectableRow) (T, error) {
+		result := newRecord[T]()
+		descriptions := row.FieldDescriptions()
+		columns := make([]string, 0, len(descriptions))
+		for _, desc := range descriptions {
+			columns = append(columns, desc.Name)
 		}
 
-		// Extract valid 'db' tags (ignoring "-" and empty values).
-		if tag, ok := field.Tag.Lookup("db"); ok && !skipped(tag, only, exclude) {
-			columns = append(columns, quoteField(tag))
+		pointers, err := structPointers(m, &result, columns)
+		if err != nil {
+			return result, err
 		}
+
+		err = row.Scan(pointers...)
+		return result, err
 	}
-	return columns
 }
 
-// structValues extracts field values from a struct based on the `db` tag, excluding unexported fields.
-func structValues(v any, only, exclude []string) []any {
-	val := reflect.Indirect(reflect.ValueOf(v))
-	if val.Kind() != reflect.Struct {
-		return nil
+// returningClause generates the RETURNING clause for the given columns, or an empty string if there are none.
+func returningClause(columns []string) string {
+	if len(columns) == 0 {
+		return ""
 	}
 
-	typ := val.Type()
-	values := make([]any, 0, typ.NumField())
+	quoted := make([]string, 0, len(columns))
+	for _, col := range columns {
+		quoted = append(quoted, quoteField(col))
+	}
+	return " RETURNING " + strings.Join(quoted, ",")
+}
+
+// execReturning executes a command with a RETURNING clause and scans the first returned row into `dest`.
+// The Executable must also implement Readable.
+func execReturning(ctx context.Context, e Executable, sql string, dest any, columns []string, args ...any) (pgconn.CommandTag, error) {
+	r, ok := e.(Readable)
+	if !ok {
+		return pgconn.CommandTag{}, ErrReturningUnsupported
+	}
 
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
+	pointers, err := structPointers(mapperOf(e), dest, columns)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
 
-		// Skip unexported fields.
-		if !field.IsExported() {
-			continue
-		}
+	// The command is a write even though it returns rows, so a Cluster must run it on the primary
+	rows, err := r.Query(ForcePrimary(ctx), sql, args...)
+	if err != nil {
+		return pgconn.CommandTag{}, classify(err)
+	}
+	defer rows.Close()
 
-		// Add values for valid 'db' tags (not "-" or empty).
-		if tag, ok := field.Tag.Lookup("db"); ok && !skipped(tag, only, exclude) {
-			values = append(values, val.Field(i).Interface())
+	if rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return pgconn.CommandTag{}, classify(err)
 		}
 	}
-	return values
+
+	// Close drains the remaining rows and finalizes the command tag
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return pgconn.CommandTag{}, classify(err)
+	}
+	return rows.CommandTag(), nil
 }
 
-// skipped checks if a struct field's `db` tag should be skipped based on `only` and `exclude` lists.
-func skipped(tag string, only, exclude []string) bool {
-	if tag == "-" || tag == "" ||
-		(len(only) > 0 && !slices.Contains(only, tag)) ||
-		(len(exclude) > 0 && slices.Contains(exclude, tag)) {
-		return true
+// softDeleteColumn returns the column name of the T field tagged with the `softdelete` option, or an empty string.
+func softDeleteColumn[T any](m *mapper.Mapper) string {
+	if meta := metadataOf[T](m); meta != nil {
+		return meta.SoftDelete
 	}
+	return ""
+}
 
-	return false
+// primaryKeys returns the column names of the T fields tagged with the `pk` option.
+func primaryKeys[T any](m *mapper.Mapper) []string {
+	if meta := metadataOf[T](m); meta != nil {
+		return meta.Keys
+	}
+	return nil
 }
 
 // quoteField wraps a column name in double quotes for SQL compatibility.
@@ -95,11 +185,25 @@ func quoteField(field string) string {
 	return fmt.Sprintf(`"%s"`, field)
 }
 
+// quoteTable quotes each part of a table name, e.g. a schema qualified table name.
+func quoteTable(table string) string {
+	parts := strings.Split(table, ".")
+	for i, part := range parts {
+		parts[i] = quoteField(part)
+	}
+	return strings.Join(parts, ".")
+}
+
 // normalizePlaceholder converts '?' placeholders in SQL to PostgreSQL-style numbered parameters ($1, $2, ...).
 func normalizePlaceholder(query string) string {
+	return normalizePlaceholderFrom(query, 0)
+}
+
+// normalizePlaceholderFrom converts '?' placeholders in SQL to numbered parameters starting after `offset`.
+func normalizePlaceholderFrom(query string, offset int) string {
 	var builder strings.Builder
 	builder.Grow(len(query) + 10)
-	counter := 0
+	counter := offset
 
 	for i := 0; i < len(query); i++ {
 		if query[i] == '?' {